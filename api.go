@@ -0,0 +1,133 @@
+package sweet
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const apiPrefix = "/api/v1/devices"
+
+// APIDevice is the JSON view of a device's status. Device credentials are never included.
+type APIDevice struct {
+	Hostname     string                   `json:"hostname"`
+	Method       string                   `json:"method"`
+	State        string                   `json:"state"`
+	When         time.Time                `json:"when"`
+	ErrorMessage string                   `json:"errorMessage,omitempty"`
+	Configs      []string                 `json:"configs"`
+	Diffs        map[string]APIConfigDiff `json:"diffs,omitempty"`
+}
+
+// APIConfigDiff is the JSON view of a ConfigDiff.
+type APIConfigDiff struct {
+	Diff    string `json:"diff"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	NewFile bool   `json:"newFile"`
+}
+
+// APIConfig is a single collected config.
+type APIConfig struct {
+	Hostname string    `json:"hostname"`
+	Name     string    `json:"name"`
+	When     time.Time `json:"when"`
+	Config   string    `json:"config"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func (s DeviceStatusState) String() string {
+	switch s {
+	case StatePending:
+		return "pending"
+	case StateError:
+		return "error"
+	case StateTimeout:
+		return "timeout"
+	case StateSuccess:
+		return "success"
+	case StateHostKeyChanged:
+		return "hostkey-changed"
+	}
+	return "unknown"
+}
+
+//// Route /api/v1/devices requests
+func webAPI(w http.ResponseWriter, r *http.Request, Opts *SweetOptions) {
+	if r.Method != "GET" {
+		apiWrite(w, http.StatusMethodNotAllowed, apiError{"Only GET is supported."})
+		return
+	}
+	statuses := Opts.Status.GetAll()
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix), "/")
+	if len(path) == 0 {
+		hostnames := make([]string, 0, len(statuses))
+		for hostname := range statuses {
+			hostnames = append(hostnames, hostname)
+		}
+		sort.Strings(hostnames)
+		devices := make([]APIDevice, 0, len(hostnames))
+		for _, hostname := range hostnames {
+			devices = append(devices, newAPIDevice(statuses[hostname]))
+		}
+		apiWrite(w, http.StatusOK, devices)
+		return
+	}
+
+	parts := strings.SplitN(path, "/", 3)
+	stat, ok := statuses[parts[0]]
+	if !ok {
+		apiWrite(w, http.StatusNotFound, apiError{fmt.Sprintf("Unknown device: %s", parts[0])})
+		return
+	}
+	switch {
+	case len(parts) == 1:
+		apiWrite(w, http.StatusOK, newAPIDevice(stat))
+	case len(parts) == 2 && parts[1] == "diffs":
+		apiWrite(w, http.StatusOK, newAPIDevice(stat).Diffs)
+	case len(parts) == 3 && parts[1] == "configs":
+		config, ok := stat.Configs[parts[2]]
+		if !ok {
+			apiWrite(w, http.StatusNotFound, apiError{fmt.Sprintf("No %s config for device %s", parts[2], parts[0])})
+			return
+		}
+		apiWrite(w, http.StatusOK, APIConfig{Hostname: parts[0], Name: parts[2], When: stat.When, Config: config})
+	default:
+		apiWrite(w, http.StatusNotFound, apiError{fmt.Sprintf("Unknown API path: %s", r.URL.Path)})
+	}
+}
+
+func newAPIDevice(stat DeviceStatus) APIDevice {
+	d := APIDevice{
+		Hostname:     stat.Device.Hostname,
+		Method:       stat.Device.Method,
+		State:        stat.State.String(),
+		When:         stat.When,
+		ErrorMessage: stat.ErrorMessage,
+		Configs:      make([]string, 0, len(stat.Configs)),
+		Diffs:        make(map[string]APIConfigDiff, len(stat.Diffs)),
+	}
+	for name := range stat.Configs {
+		d.Configs = append(d.Configs, name)
+	}
+	sort.Strings(d.Configs)
+	for name, diff := range stat.Diffs {
+		d.Diffs[name] = APIConfigDiff{Diff: diff.Diff, Added: diff.Added, Removed: diff.Removed, NewFile: diff.NewFile}
+	}
+	return d
+}
+
+func apiWrite(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
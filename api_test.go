@@ -0,0 +1,97 @@
+package sweet
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func apiTestOpts() *SweetOptions {
+	Opts := &SweetOptions{Status: &Status{Status: make(map[string]DeviceStatus)}}
+	Opts.Status.Set(DeviceStatus{
+		Device:  DeviceConfig{Hostname: "sw1.example.com", Method: "cisco", Config: map[string]string{"pass": "secret"}},
+		State:   StateSuccess,
+		When:    time.Now(),
+		Configs: map[string]string{"config": "hostname sw1", "version": "IOS 15"},
+		Diffs:   map[string]ConfigDiff{"config": {Diff: "+hostname sw1", Added: 1}},
+	})
+	Opts.Status.Set(DeviceStatus{
+		Device:       DeviceConfig{Hostname: "sw2.example.com", Method: "junos"},
+		State:        StateError,
+		ErrorMessage: "Missing password prompt",
+	})
+	return Opts
+}
+
+func apiGet(t *testing.T, Opts *SweetOptions, path string, v interface{}) int {
+	w := httptest.NewRecorder()
+	webAPI(w, httptest.NewRequest("GET", path, nil), Opts)
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Bad content type for %s: %s", path, w.Header().Get("Content-Type"))
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Errorf("Bad JSON from %s: %s", path, err.Error())
+	}
+	return w.Code
+}
+
+func TestAPIDevices(t *testing.T) {
+	Opts := apiTestOpts()
+	devices := []APIDevice{}
+	if code := apiGet(t, Opts, "/api/v1/devices", &devices); code != 200 {
+		t.Errorf("Bad status code: %d", code)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("Expected 2 devices but got %d", len(devices))
+	}
+	if devices[0].Hostname != "sw1.example.com" || devices[0].State != "success" {
+		t.Errorf("Bad first device: %s %s", devices[0].Hostname, devices[0].State)
+	}
+	if len(devices[0].Configs) != 2 || devices[0].Configs[0] != "config" {
+		t.Errorf("Bad config names: %v", devices[0].Configs)
+	}
+	if devices[1].State != "error" || devices[1].ErrorMessage != "Missing password prompt" {
+		t.Errorf("Bad error device: %s %s", devices[1].State, devices[1].ErrorMessage)
+	}
+}
+
+func TestAPIDevice(t *testing.T) {
+	Opts := apiTestOpts()
+	device := APIDevice{}
+	if code := apiGet(t, Opts, "/api/v1/devices/sw1.example.com", &device); code != 200 {
+		t.Errorf("Bad status code: %d", code)
+	}
+	if device.Method != "cisco" || device.Diffs["config"].Added != 1 {
+		t.Errorf("Bad device: %+v", device)
+	}
+
+	e := apiError{}
+	if code := apiGet(t, Opts, "/api/v1/devices/nope.example.com", &e); code != 404 {
+		t.Errorf("Expected 404 for unknown device but got %d", code)
+	}
+}
+
+func TestAPIConfigsAndDiffs(t *testing.T) {
+	Opts := apiTestOpts()
+	config := APIConfig{}
+	if code := apiGet(t, Opts, "/api/v1/devices/sw1.example.com/configs/version", &config); code != 200 {
+		t.Errorf("Bad status code: %d", code)
+	}
+	if config.Config != "IOS 15" || config.Name != "version" {
+		t.Errorf("Bad config: %+v", config)
+	}
+
+	e := apiError{}
+	if code := apiGet(t, Opts, "/api/v1/devices/sw1.example.com/configs/nope", &e); code != 404 {
+		t.Errorf("Expected 404 for unknown config but got %d", code)
+	}
+
+	diffs := map[string]APIConfigDiff{}
+	if code := apiGet(t, Opts, "/api/v1/devices/sw1.example.com/diffs", &diffs); code != 200 {
+		t.Errorf("Bad status code: %d", code)
+	}
+	if diffs["config"].Diff != "+hostname sw1" {
+		t.Errorf("Bad diffs: %+v", diffs)
+	}
+}
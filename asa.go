@@ -0,0 +1,107 @@
+package sweet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// asaFailoverLines are the lines of "show failover" that only change when the
+// pair's setup does, leaving out counters and poll timers.
+var asaFailoverLines = regexp.MustCompile(`^\s*(Failover (On|Off)\s*$|Failover unit |Failover LAN Interface:|Version: )`)
+
+// asaFailoverRoleLines change whenever the pair fails over
+var asaFailoverRoleLines = regexp.MustCompile(`^\s*(Last Failover at:|This host: |Other host: |Group [0-9]+\s+State:)`)
+
+//// Save the failover setup of an ASA pair, and with "asa-failover-roles = true"
+//// which unit is active, so a failover shows up as a change
+func asaCollectFailover(c *SSHCollector, device DeviceConfig, prompt *regexp.Regexp, result map[string]string) error {
+	c.Send <- "show failover\n"
+	output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+	if err != nil {
+		return fmt.Errorf("Command 'show failover' failed: %s", err.Error())
+	}
+	roles := device.Config["asa-failover-roles"] == "true"
+	if failover := parseASAFailover(cleanCommandOutput(output, "show failover"), roles); len(failover) > 0 {
+		result["failover"] = failover
+	}
+	return nil
+}
+
+// parseASAFailover keeps the stable lines of "show failover", and the role
+// lines if asked to, or nothing when failover is off.
+func parseASAFailover(output string, roles bool) string {
+	kept := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r ")
+		if strings.TrimSpace(line) == "Failover Off" {
+			return ""
+		}
+		if asaFailoverLines.MatchString(line) || (roles && asaFailoverRoleLines.MatchString(line)) {
+			kept = append(kept, strings.TrimSpace(line))
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+//// Save the running config of every security context on a multi-context ASA
+func asaCollectContexts(c *SSHCollector, device DeviceConfig, systemPrompt *regexp.Regexp, result map[string]string) error {
+	c.Send <- "show context\n"
+	output, err := expectSaveRegexpTimeout(systemPrompt, c.Receive, device.CommandTimeout)
+	if err != nil {
+		return fmt.Errorf("Command 'show context' failed: %s", err.Error())
+	}
+	contexts := parseASAContexts(cleanCommandOutput(output, "show context"))
+	if len(contexts) == 0 {
+		return fmt.Errorf("No security contexts found - is %s in multiple context mode?", device.Hostname)
+	}
+
+	for _, context := range contexts {
+		// each context has its own prompt, e.g. "asa/admin#"
+		c.Send <- "changeto context " + context + "\n"
+		_, output, err := expectMultiRegexp([]*regexp.Regexp{ciscoPromptEnd}, c.Receive)
+		if err != nil {
+			return fmt.Errorf("Command 'changeto context %s' failed: %s", context, err.Error())
+		}
+		prompt := promptRegexp(lastLine(output))
+		c.Send <- "terminal pager 0\n"
+		if err := expectRegexp(prompt, c.Receive); err != nil {
+			return fmt.Errorf("Command 'terminal pager 0' in context %s failed: %s", context, err.Error())
+		}
+		c.Send <- "show running-config\n"
+		config, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+		if err != nil {
+			return fmt.Errorf("Command 'show running-config' in context %s failed: %s", context, err.Error())
+		}
+		config = cleanCommandOutput(config, "show running-config")
+		result["context-"+context] = strings.TrimSpace(strings.TrimPrefix(config, "Building configuration..."))
+	}
+
+	c.Send <- "changeto system\n"
+	if err := expectRegexp(systemPrompt, c.Receive); err != nil {
+		return fmt.Errorf("Command 'changeto system' failed: %s", err.Error())
+	}
+	return nil
+}
+
+// parseASAContexts reads context names from "show context". The current
+// context is marked with a "*".
+func parseASAContexts(output string) []string {
+	contexts := []string{}
+	inTable := false
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "Context Name") {
+			inTable = true
+			continue
+		}
+		if !inTable || strings.HasPrefix(strings.TrimSpace(line), "Total ") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		contexts = append(contexts, strings.TrimPrefix(fields[0], "*"))
+	}
+	return contexts
+}
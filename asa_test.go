@@ -0,0 +1,111 @@
+package sweet
+
+import (
+	"testing"
+)
+
+const asaTestContexts = "Context Name      Class      Interfaces           Mode         URL\r\n" +
+	"*admin            default    Management0/0        Routed       disk0:/admin.cfg\r\n" +
+	" dmz              default    GigabitEthernet0/2   Routed       disk0:/dmz.cfg\r\n" +
+	"\r\nTotal active Security Contexts: 2\r\n"
+
+func TestASAContexts(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "asa/pri/act#",
+		Responses: map[string]string{
+			"show running-config": ": Saved\r\n:\r\nASA Version 9.8(4)\r\nhostname asa\r\n",
+			"show version":        "Cisco Adaptive Security Appliance Software Version 9.8(4)\r\n",
+			"show context":        asaTestContexts,
+		},
+	}
+	shell.Hooks = map[string]func(){
+		"changeto context admin": func() {
+			shell.Prompt = "asa/pri/act/admin#"
+			shell.Responses["show running-config"] = ": Saved\r\nhostname admin\r\n"
+		},
+		"changeto context dmz": func() {
+			shell.Prompt = "asa/pri/act/dmz#"
+			shell.Responses["show running-config"] = ": Saved\r\nhostname dmz\r\naccess-list outside_in extended permit tcp any any eq 443\r\n"
+		},
+		"changeto system": func() { shell.Prompt = "asa/pri/act#" },
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Config["asa-contexts"] = "true"
+
+	result, err := newCiscoCollector().Collect(d)
+	if err != nil {
+		t.Fatalf("ASA collection failed: %s", err.Error())
+	}
+	if result["context-admin"] != ": Saved\nhostname admin" {
+		t.Errorf("Bad admin context result: %q", result["context-admin"])
+	}
+	if result["context-dmz"] != ": Saved\nhostname dmz\naccess-list outside_in extended permit tcp any any eq 443" {
+		t.Errorf("Bad dmz context result: %q", result["context-dmz"])
+	}
+	if len(result["config"]) == 0 || len(result["version"]) == 0 {
+		t.Errorf("Missing system context results")
+	}
+	if shell.Received[len(shell.Received)-1] != "changeto system" {
+		t.Errorf("Collector did not return to the system context: %v", shell.Received)
+	}
+}
+
+func TestASAParseContexts(t *testing.T) {
+	contexts := parseASAContexts(cleanCommandOutput("show context\r\n"+asaTestContexts+"asa#", "show context"))
+	if len(contexts) != 2 || contexts[0] != "admin" || contexts[1] != "dmz" {
+		t.Errorf("Bad contexts: %v", contexts)
+	}
+}
+
+const asaTestFailover = "Failover On \r\n" +
+	"Failover unit Primary\r\n" +
+	"Failover LAN Interface: folink GigabitEthernet0/3 (up)\r\n" +
+	"Reconnect timeout 0:00:00\r\n" +
+	"Unit Poll frequency 1 seconds, holdtime 15 seconds\r\n" +
+	"Version: Ours 9.8(4), Mate 9.8(4)\r\n" +
+	"Last Failover at: 10:01:02 UTC Oct 1 2026\r\n" +
+	"\tThis host: Primary - Standby Ready\r\n" +
+	"\t\tActive time: 0 (sec)\r\n" +
+	"\tOther host: Secondary - Active\r\n" +
+	"\t\tActive time: 1234567 (sec)\r\n" +
+	"Stateful Failover Logical Update Statistics\r\n" +
+	"\tGeneral         12345      0          12340      0\r\n"
+
+func TestASAFailover(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "asa/pri/stby#",
+		Responses: map[string]string{
+			"show running-config": ": Saved\r\n:\r\nASA Version 9.8(4)\r\nhostname asa\r\n",
+			"show version":        "Cisco Adaptive Security Appliance Software Version 9.8(4)\r\n",
+			"show failover":       asaTestFailover,
+		},
+	}
+	setup := "Failover On\nFailover unit Primary\nFailover LAN Interface: folink GigabitEthernet0/3 (up)\nVersion: Ours 9.8(4), Mate 9.8(4)"
+	roles := "\nLast Failover at: 10:01:02 UTC Oct 1 2026\nThis host: Primary - Standby Ready\nOther host: Secondary - Active"
+	for _, option := range []string{"false", "true"} {
+		addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+		d := testSSHDevice(addr)
+		d.Config["asa-failover-roles"] = option
+		result, err := newCiscoCollector().Collect(d)
+		if err != nil {
+			t.Fatalf("ASA collection failed: %s", err.Error())
+		}
+		expected := setup
+		if option == "true" {
+			expected += roles
+		}
+		if result["failover"] != expected {
+			t.Errorf("Bad failover result with asa-failover-roles = %s: %q", option, result["failover"])
+		}
+	}
+}
+
+func TestASAParseFailover(t *testing.T) {
+	if failover := parseASAFailover("Failover Off\r\nFailover unit Secondary\r\nFailover LAN Interface: not Configured\r\n", true); failover != "" {
+		t.Errorf("A single ASA should not save its failover state: %q", failover)
+	}
+	if failover := parseASAFailover("Failover On\r\nGroup 1           State:          Active\r\n", true); failover != "Failover On\nGroup 1           State:          Active" {
+		t.Errorf("Bad failover groups: %q", failover)
+	}
+}
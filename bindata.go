@@ -4405,91 +4405,95 @@ func static_jquery_min_js() ([]byte, error) {
 
 func tmpl_index_html() ([]byte, error) {
 	return bindata_read([]byte{
-		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xc4, 0x56,
-		0x5f, 0x6f, 0xdb, 0x36, 0x10, 0x7f, 0xcf, 0xa7, 0x60, 0xb4, 0x0c, 0xb1,
-		0xd1, 0x49, 0x6a, 0x80, 0x66, 0xe8, 0x36, 0x59, 0x40, 0xe1, 0x64, 0x68,
-		0x80, 0xa4, 0x1b, 0xea, 0x0c, 0xdb, 0x50, 0xf4, 0x81, 0x16, 0x4f, 0x16,
-		0x53, 0x8a, 0x54, 0x49, 0xda, 0x99, 0x61, 0xe8, 0xbb, 0xef, 0x28, 0x5a,
-		0xb1, 0x64, 0x39, 0x5e, 0x57, 0x0c, 0xd8, 0x8b, 0x2d, 0x92, 0xbf, 0xfb,
-		0xf1, 0xfe, 0xf3, 0x92, 0xd3, 0xab, 0x5f, 0xa6, 0xf7, 0x7f, 0xfe, 0x7a,
-		0x4d, 0x0a, 0x5b, 0x8a, 0xf4, 0x24, 0x71, 0x7f, 0x44, 0x50, 0xb9, 0x98,
-		0x04, 0x20, 0x83, 0xf4, 0x84, 0x90, 0xa4, 0x00, 0xca, 0xdc, 0x07, 0x7e,
-		0x96, 0x60, 0x29, 0xc9, 0x0a, 0xaa, 0x0d, 0xd8, 0x49, 0xb0, 0xb4, 0x79,
-		0xf8, 0x3a, 0xe8, 0x1e, 0x15, 0xd6, 0x56, 0x21, 0x7c, 0x5e, 0xf2, 0xd5,
-		0x24, 0xf8, 0x23, 0xfc, 0xed, 0x4d, 0x38, 0x55, 0x65, 0x45, 0x2d, 0x9f,
-		0x0b, 0x08, 0x48, 0xa6, 0xa4, 0x05, 0x89, 0x72, 0x37, 0xd7, 0x13, 0x60,
-		0x0b, 0xe8, 0x49, 0x4a, 0x5a, 0xc2, 0x24, 0x58, 0x71, 0x78, 0xac, 0x94,
-		0xb6, 0x1d, 0xf0, 0x23, 0x67, 0xb6, 0x98, 0x30, 0x58, 0xf1, 0x0c, 0xc2,
-		0x66, 0xf1, 0x1d, 0xe1, 0x92, 0x5b, 0x4e, 0x45, 0x68, 0x32, 0x2a, 0x60,
-		0x72, 0x71, 0x80, 0x88, 0x81, 0xc9, 0x34, 0xaf, 0x2c, 0x57, 0xb2, 0xc3,
-		0x35, 0x7b, 0x04, 0xb0, 0xc4, 0x58, 0x6a, 0x97, 0x86, 0x30, 0x6a, 0x8a,
-		0xb9, 0xa2, 0x9a, 0x1d, 0x10, 0xa7, 0x4b, 0x5b, 0x28, 0xdd, 0x91, 0x44,
-		0x8c, 0x07, 0x59, 0x6e, 0x05, 0xa4, 0x9b, 0x4d, 0x74, 0xef, 0x3e, 0xea,
-		0x3a, 0x89, 0xfd, 0xce, 0xf6, 0xf8, 0x34, 0x0c, 0xc9, 0x74, 0x36, 0x23,
-		0x61, 0xb8, 0x25, 0x15, 0x5c, 0x7e, 0x22, 0x1a, 0xc4, 0x24, 0x30, 0x76,
-		0x2d, 0xc0, 0x14, 0xa8, 0x41, 0x40, 0x0a, 0x0d, 0xb9, 0xdb, 0x41, 0xd7,
-		0x64, 0xf1, 0x5c, 0x29, 0x6b, 0xac, 0xa6, 0x55, 0x54, 0x72, 0x19, 0x65,
-		0xc6, 0x04, 0x5f, 0x21, 0x1b, 0xda, 0x02, 0x4a, 0xe8, 0x30, 0xec, 0xf4,
-		0x79, 0x7b, 0x7f, 0x77, 0x7b, 0x49, 0x4c, 0xc1, 0x4b, 0x42, 0x25, 0x23,
-		0xef, 0xc1, 0x54, 0x4a, 0xb2, 0xe8, 0xc1, 0x90, 0x9b, 0xeb, 0xd7, 0xc4,
-		0x2c, 0x2b, 0xe7, 0x71, 0xa2, 0xf2, 0x2d, 0x10, 0x04, 0x12, 0x49, 0x6b,
-		0x1a, 0x70, 0x09, 0x8c, 0x53, 0xf2, 0x79, 0x09, 0x9a, 0x83, 0xd9, 0x59,
-		0x85, 0xb4, 0x1f, 0x78, 0x4e, 0x84, 0x45, 0x0a, 0xf2, 0xc3, 0x47, 0xbf,
-		0x8b, 0xfb, 0xde, 0xe9, 0xc4, 0xe8, 0xec, 0x49, 0x43, 0x97, 0x50, 0x97,
-		0x78, 0xf9, 0x0a, 0x2f, 0x0c, 0xd2, 0x24, 0xf6, 0x90, 0x63, 0x12, 0x7a,
-		0xab, 0xa0, 0xb3, 0x65, 0x28, 0x93, 0x9c, 0x7e, 0x00, 0xc9, 0x78, 0xfe,
-		0xd1, 0x2b, 0x93, 0xc4, 0x3e, 0x3d, 0xdd, 0xe7, 0x5c, 0xb1, 0x75, 0x6b,
-		0x38, 0xe3, 0x2b, 0x92, 0x09, 0x6a, 0xcc, 0x24, 0x70, 0x41, 0xa4, 0x5c,
-		0x82, 0x6e, 0xbd, 0xd2, 0x3f, 0x76, 0xf2, 0xcd, 0x19, 0x21, 0xc3, 0x43,
-		0xad, 0x1e, 0x3b, 0x27, 0xfb, 0xbc, 0x22, 0x2c, 0x59, 0xf8, 0x7d, 0x0f,
-		0xe0, 0xea, 0xe5, 0x22, 0xf5, 0x89, 0xd6, 0xcd, 0x12, 0xdc, 0xec, 0xd2,
-		0xc4, 0xc8, 0xf3, 0xaf, 0x79, 0x5f, 0xb5, 0x88, 0x6a, 0x29, 0x44, 0xa8,
-		0xf9, 0xa2, 0xb0, 0x7b, 0x18, 0x42, 0x66, 0xa0, 0x57, 0xa0, 0xd1, 0xaf,
-		0x56, 0x2b, 0xb9, 0x48, 0x13, 0x53, 0x51, 0xd9, 0x8a, 0x59, 0xf8, 0xcb,
-		0x86, 0x66, 0x99, 0x65, 0xe0, 0x32, 0x04, 0xb5, 0xbb, 0x5b, 0xbf, 0x55,
-		0xc6, 0xba, 0x94, 0x77, 0x2a, 0x3a, 0xa8, 0x73, 0xb6, 0x97, 0x24, 0x96,
-		0x97, 0x40, 0xb8, 0xf9, 0x42, 0xaa, 0x77, 0xea, 0xb1, 0xe1, 0xd8, 0x62,
-		0x3d, 0x59, 0x5f, 0xff, 0xb8, 0x78, 0x75, 0xc4, 0x07, 0xbd, 0x65, 0x7f,
-		0x51, 0xe8, 0x83, 0x81, 0xeb, 0xc7, 0x26, 0xb1, 0x14, 0xbb, 0xcc, 0x93,
-		0x7e, 0x6e, 0xd1, 0x8f, 0x9c, 0xdd, 0xf5, 0xb1, 0xdd, 0x9e, 0xde, 0xf7,
-		0x1f, 0xc2, 0xd2, 0xab, 0xa6, 0xd9, 0x60, 0x65, 0x17, 0x87, 0x4e, 0x6f,
-		0xa9, 0xb1, 0x64, 0xaa, 0x84, 0x80, 0xcc, 0x02, 0x3b, 0x8e, 0x2a, 0xb0,
-		0x91, 0x3e, 0x8f, 0x99, 0x35, 0x8d, 0xe8, 0xb9, 0xd3, 0x17, 0x71, 0x38,
-		0x3c, 0xc2, 0x1d, 0xdd, 0x77, 0xe2, 0xc0, 0xac, 0xcd, 0x86, 0x68, 0x77,
-		0x2d, 0x89, 0xbc, 0x1d, 0x86, 0xd4, 0x75, 0xcf, 0x0f, 0xba, 0x75, 0x12,
-		0x86, 0xed, 0x77, 0x98, 0x47, 0x53, 0xb7, 0xaa, 0xeb, 0xfd, 0x74, 0xb3,
-		0x2c, 0x6d, 0x43, 0x8f, 0x40, 0xcf, 0x15, 0xf5, 0xf2, 0xa5, 0x8d, 0xb5,
-		0x1d, 0xb8, 0x95, 0xed, 0x1b, 0xb4, 0xd9, 0x60, 0xbb, 0x68, 0x6e, 0xbb,
-		0x96, 0x2e, 0x34, 0x53, 0x25, 0xf3, 0x5b, 0x6c, 0x6f, 0x3d, 0xd5, 0xb6,
-		0xd2, 0x74, 0xdb, 0xe2, 0xb0, 0x6e, 0x73, 0xbe, 0x30, 0xf1, 0xa1, 0xdb,
-		0x9b, 0x9c, 0x7b, 0x0a, 0xc2, 0x3d, 0xa6, 0xea, 0xcf, 0x4a, 0x97, 0xd4,
-		0xe2, 0xa2, 0xae, 0x09, 0x5d, 0xa8, 0x24, 0xa6, 0x43, 0x1d, 0x40, 0x18,
-		0x38, 0x70, 0xe3, 0x3b, 0xc0, 0x92, 0x19, 0x82, 0x25, 0xdb, 0xc3, 0x7e,
-		0x95, 0xa5, 0x57, 0x3c, 0x3f, 0x62, 0x69, 0x9b, 0xae, 0x6a, 0xb1, 0xf0,
-		0xd0, 0x00, 0xdf, 0x25, 0x4b, 0x43, 0x4b, 0xf5, 0xc2, 0x3d, 0xb1, 0xdf,
-		0xb4, 0x31, 0x9a, 0xcd, 0x6e, 0xae, 0xea, 0x3a, 0xc4, 0xc6, 0x97, 0x4f,
-		0xfd, 0xa3, 0x34, 0x28, 0x7d, 0x77, 0x77, 0xb4, 0x4d, 0xb9, 0xff, 0xd5,
-		0x23, 0xa8, 0x86, 0x4f, 0xed, 0x3b, 0xec, 0x0e, 0x74, 0xe1, 0x5f, 0xc9,
-		0xff, 0xd6, 0x73, 0x9d, 0x5e, 0x74, 0x2e, 0xe8, 0x1c, 0xdc, 0xcc, 0x82,
-		0xbf, 0x61, 0xa5, 0x79, 0x49, 0xf5, 0xfa, 0x3c, 0x7d, 0x81, 0x4a, 0xbc,
-		0x61, 0xcc, 0x19, 0x7f, 0xa8, 0x19, 0x1d, 0x25, 0x61, 0xce, 0x85, 0xfa,
-		0x3c, 0x0d, 0x91, 0xe3, 0x3d, 0x94, 0x6a, 0xf5, 0x3c, 0xcb, 0x97, 0x38,
-		0x65, 0x50, 0xb3, 0x58, 0x81, 0x9c, 0xed, 0xca, 0xef, 0x40, 0x68, 0xdb,
-		0xbc, 0xe0, 0x32, 0x57, 0x01, 0x69, 0x5e, 0x7f, 0x9c, 0x69, 0xb8, 0xa9,
-		0x04, 0x5d, 0xff, 0x48, 0xa4, 0x92, 0x30, 0xac, 0x56, 0x1c, 0x56, 0x84,
-		0x53, 0x71, 0x72, 0x49, 0xfc, 0xcc, 0x74, 0xf1, 0xf2, 0xe5, 0xb7, 0x69,
-		0x52, 0xe9, 0x66, 0x5c, 0x71, 0xce, 0x74, 0x46, 0xb8, 0xe5, 0x3f, 0x29,
-		0x88, 0x1d, 0x04, 0xad, 0xea, 0xb6, 0x0d, 0x44, 0xb8, 0x88, 0xec, 0x40,
-		0xfd, 0x3e, 0x7d, 0xb2, 0xfb, 0x6e, 0x26, 0x8e, 0x38, 0x7a, 0x7a, 0x73,
-		0x9b, 0x99, 0x61, 0x37, 0x8b, 0x3c, 0x74, 0x86, 0x88, 0x03, 0x4f, 0xff,
-		0x83, 0x9b, 0x34, 0xd6, 0xcf, 0xbd, 0xfc, 0xed, 0xe2, 0x6c, 0x94, 0x2f,
-		0x65, 0xe6, 0x66, 0xbb, 0xd1, 0x98, 0x6c, 0xdc, 0xd9, 0xd9, 0x28, 0x88,
-		0x3a, 0x35, 0x34, 0x8e, 0x32, 0xc1, 0xb3, 0x4f, 0x1d, 0xd8, 0xa6, 0xd5,
-		0xfc, 0x6c, 0x74, 0x36, 0xb2, 0x05, 0x37, 0xe3, 0xc8, 0xd5, 0xd9, 0x28,
-		0xf0, 0x85, 0x16, 0x8c, 0xc7, 0x5b, 0xf9, 0xd1, 0xf8, 0x27, 0x07, 0xad,
-		0xc7, 0x27, 0xb5, 0xff, 0x22, 0x5d, 0x25, 0x92, 0xd8, 0xcf, 0x18, 0x49,
-		0xec, 0xc7, 0xe5, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0xdc, 0x62, 0x25,
-		0xa3, 0x3f, 0x0b, 0x00, 0x00,
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xb4, 0x56,
+		0x6d, 0x6f, 0xdb, 0xb6, 0x13, 0x7f, 0xef, 0x4f, 0x71, 0xd1, 0x3f, 0x7f,
+		0xd4, 0x46, 0x27, 0xa9, 0x01, 0x9a, 0xa1, 0xdb, 0x24, 0x01, 0x85, 0x93,
+		0xa1, 0x01, 0x92, 0x6e, 0xa8, 0x33, 0xec, 0xa1, 0xe8, 0x0b, 0x5a, 0x3c,
+		0x59, 0x4c, 0x28, 0x52, 0x25, 0xcf, 0xce, 0x0c, 0x41, 0xdf, 0x7d, 0xa0,
+		0x64, 0xd9, 0x92, 0xed, 0xa4, 0x69, 0xb1, 0x05, 0x41, 0x42, 0xf2, 0x7e,
+		0x77, 0xfc, 0xdd, 0x83, 0x8e, 0x17, 0x9d, 0x5c, 0xfc, 0x32, 0xbd, 0xfd,
+		0xf3, 0xd7, 0x4b, 0xc8, 0xa9, 0x90, 0xc9, 0x28, 0x72, 0xff, 0x40, 0x32,
+		0xb5, 0x88, 0x3d, 0x54, 0x5e, 0x32, 0x02, 0x88, 0x72, 0x64, 0xdc, 0x2d,
+		0x00, 0xa2, 0x02, 0x89, 0x41, 0x9a, 0x33, 0x63, 0x91, 0x62, 0x6f, 0x49,
+		0x99, 0xff, 0xc6, 0xeb, 0x8b, 0x72, 0xa2, 0xd2, 0xc7, 0xcf, 0x4b, 0xb1,
+		0x8a, 0xbd, 0x3f, 0xfc, 0xdf, 0xde, 0xfa, 0x53, 0x5d, 0x94, 0x8c, 0xc4,
+		0x5c, 0xa2, 0x07, 0xa9, 0x56, 0x84, 0x8a, 0x62, 0xef, 0xea, 0x32, 0x46,
+		0xbe, 0xc0, 0x81, 0xa6, 0x62, 0x05, 0xc6, 0xde, 0x4a, 0xe0, 0x43, 0xa9,
+		0x0d, 0xf5, 0xc0, 0x0f, 0x82, 0x53, 0x1e, 0x73, 0x5c, 0x89, 0x14, 0xfd,
+		0x66, 0xf3, 0x1d, 0x08, 0x25, 0x48, 0x30, 0xe9, 0xdb, 0x94, 0x49, 0x8c,
+		0xcf, 0x8e, 0x18, 0xe2, 0x68, 0x53, 0x23, 0x4a, 0x12, 0x5a, 0xf5, 0x6c,
+		0xcd, 0x1e, 0x10, 0x09, 0x2c, 0x31, 0x5a, 0x5a, 0xe0, 0xcc, 0xe6, 0x73,
+		0xcd, 0x0c, 0x3f, 0xa2, 0xce, 0x96, 0x94, 0x6b, 0xd3, 0xd3, 0xf4, 0x92,
+		0x51, 0x0b, 0x22, 0x41, 0x12, 0x93, 0xaa, 0x0a, 0x6e, 0xdd, 0xa2, 0xae,
+		0xa3, 0xb0, 0x3d, 0xd9, 0x88, 0x4f, 0x7c, 0x1f, 0xa6, 0xb3, 0x19, 0xf8,
+		0xfe, 0xc6, 0xa8, 0x14, 0xea, 0x1e, 0x0c, 0xca, 0xd8, 0xb3, 0xb4, 0x96,
+		0x68, 0x73, 0x44, 0xf2, 0x20, 0x37, 0x98, 0xb9, 0x13, 0x46, 0x22, 0x0d,
+		0xe7, 0x5a, 0x93, 0x25, 0xc3, 0xca, 0xa0, 0x10, 0x2a, 0x48, 0xad, 0xf5,
+		0xbe, 0x41, 0xd7, 0xa7, 0x1c, 0x0b, 0xec, 0x59, 0xd8, 0xf1, 0x79, 0x77,
+		0x7b, 0x73, 0x7d, 0x0e, 0x36, 0x17, 0x05, 0x30, 0xc5, 0xe1, 0x03, 0xda,
+		0x52, 0x2b, 0x1e, 0xdc, 0x59, 0xb8, 0xba, 0x7c, 0x03, 0x76, 0x59, 0xba,
+		0x88, 0x83, 0xce, 0x36, 0x40, 0x94, 0x58, 0xa0, 0x22, 0xdb, 0x80, 0x0b,
+		0xe4, 0x82, 0xc1, 0xe7, 0x25, 0x1a, 0x81, 0x76, 0xe7, 0xd5, 0x89, 0xef,
+		0x7f, 0x14, 0x19, 0x48, 0x82, 0xab, 0x4b, 0xf8, 0xe1, 0x53, 0xcb, 0x17,
+		0x20, 0x6a, 0x83, 0x0e, 0xd6, 0xa4, 0x5b, 0x86, 0xae, 0xa0, 0xce, 0x6d,
+		0x2e, 0x56, 0xc1, 0x9d, 0xf5, 0x92, 0x28, 0x6c, 0x21, 0x4f, 0x69, 0x98,
+		0x0d, 0x41, 0xe7, 0xcb, 0xa1, 0x4e, 0x74, 0xf2, 0x11, 0x15, 0x17, 0xd9,
+		0xa7, 0x96, 0x4c, 0x14, 0xb6, 0xe5, 0xe9, 0x0a, 0x75, 0xae, 0xf9, 0xba,
+		0x73, 0x9c, 0x8b, 0x15, 0xa4, 0x92, 0x59, 0x1b, 0x7b, 0x2e, 0x89, 0x4c,
+		0x28, 0x34, 0x5d, 0x54, 0x86, 0x62, 0xa7, 0xdf, 0xc8, 0x00, 0x0e, 0x85,
+		0x46, 0x3f, 0xf4, 0x24, 0x43, 0x59, 0xaa, 0xa5, 0x5f, 0x70, 0xff, 0xfb,
+		0x01, 0xc0, 0x7d, 0x2f, 0x67, 0x49, 0x5b, 0x68, 0xfd, 0x2a, 0xc9, 0xcf,
+		0x06, 0x66, 0x42, 0x2e, 0x56, 0x5f, 0x6d, 0xf7, 0x75, 0x87, 0x28, 0x97,
+		0x52, 0xfa, 0x46, 0x2c, 0x72, 0xda, 0xc3, 0x00, 0xcc, 0xd0, 0xac, 0xd0,
+		0x40, 0x64, 0xc9, 0x68, 0xb5, 0x48, 0x22, 0x5b, 0x32, 0xd5, 0xa9, 0x11,
+		0xfe, 0x4d, 0xbe, 0x5d, 0xa6, 0x29, 0xba, 0x0a, 0xa9, 0xaa, 0xe0, 0x66,
+		0xfd, 0x4e, 0x5b, 0x72, 0x9f, 0x9e, 0xa3, 0xe8, 0xa0, 0x2e, 0x41, 0xad,
+		0x26, 0x90, 0x28, 0x10, 0x84, 0x7d, 0xa6, 0xa9, 0xf7, 0xfa, 0xa1, 0xb1,
+		0xb1, 0xc1, 0x86, 0x0e, 0xbc, 0xc7, 0x3f, 0xcc, 0x5f, 0xf7, 0x4f, 0xf6,
+		0x62, 0x30, 0xd8, 0x0e, 0x37, 0xb9, 0x39, 0x9a, 0xb8, 0x61, 0x6e, 0x22,
+		0x62, 0x73, 0x89, 0x5b, 0x7e, 0x6e, 0x33, 0x08, 0x4e, 0x44, 0xbb, 0x3e,
+		0xd6, 0xfd, 0x44, 0x64, 0x86, 0x07, 0xee, 0x28, 0x4f, 0x2e, 0x9a, 0x66,
+		0x13, 0x85, 0x94, 0x1f, 0x93, 0x5e, 0x33, 0x4b, 0x30, 0xd5, 0x52, 0x62,
+		0x4a, 0xc8, 0x9f, 0x46, 0xe5, 0x4c, 0x2d, 0x1e, 0xc7, 0xcc, 0x9a, 0x46,
+		0xf4, 0x98, 0xf4, 0x65, 0xe8, 0x1f, 0x8a, 0xa2, 0x70, 0x48, 0x39, 0x0a,
+		0x0f, 0xdc, 0xaa, 0x2a, 0x30, 0xee, 0x5a, 0x08, 0x5a, 0x3f, 0x2c, 0xd4,
+		0x75, 0x5f, 0x83, 0x4c, 0x17, 0xa4, 0xaa, 0x0a, 0x7e, 0xc7, 0x79, 0x30,
+		0x75, 0xbb, 0xba, 0x1e, 0x44, 0xcb, 0x51, 0xe0, 0x49, 0x97, 0xfa, 0xaa,
+		0xda, 0xd8, 0x0a, 0x06, 0xf5, 0xd2, 0xe5, 0x9a, 0x0e, 0xc2, 0xba, 0x77,
+		0x00, 0x50, 0x55, 0x22, 0x83, 0xe6, 0xb6, 0x4b, 0xe5, 0x52, 0x33, 0xd5,
+		0x2a, 0xbb, 0x16, 0xea, 0x7e, 0x40, 0x6d, 0xa3, 0xcd, 0x36, 0x2d, 0x2e,
+		0xd5, 0x2a, 0x13, 0x0b, 0x1b, 0x1e, 0xbb, 0xbd, 0xa9, 0xb9, 0x6d, 0x12,
+		0x6e, 0x45, 0x81, 0x3f, 0x6b, 0x53, 0x30, 0x22, 0xe4, 0x75, 0x0d, 0x6c,
+		0xa1, 0xa3, 0x90, 0x1d, 0x72, 0x40, 0x69, 0xf1, 0xc8, 0x8d, 0xef, 0x71,
+		0x85, 0xe6, 0x10, 0xac, 0xf8, 0x1e, 0xf6, 0x9b, 0x3c, 0xbd, 0x10, 0xd9,
+		0x13, 0x9e, 0x76, 0xe5, 0xaa, 0x17, 0x8b, 0x16, 0xea, 0x01, 0x67, 0xc4,
+		0x7c, 0x62, 0x66, 0xe1, 0x9e, 0xd8, 0xff, 0x75, 0x39, 0x9a, 0xcd, 0xae,
+		0x2e, 0xea, 0xda, 0xe7, 0x22, 0xcb, 0xa6, 0xed, 0x3b, 0xba, 0x97, 0x2f,
+		0xf7, 0xeb, 0x62, 0xd2, 0x96, 0xdc, 0xd7, 0x44, 0x04, 0x5c, 0x6a, 0x7a,
+		0x7a, 0xc1, 0x95, 0xfd, 0x0b, 0x8d, 0x7e, 0x7e, 0xa0, 0x8e, 0x47, 0xf5,
+		0x49, 0x32, 0xff, 0x5e, 0xb4, 0x83, 0xf6, 0x2b, 0xba, 0x41, 0x6b, 0xd9,
+		0xe2, 0x90, 0xc7, 0x7e, 0x3a, 0x6e, 0x0d, 0x53, 0xed, 0x73, 0xf2, 0xa5,
+		0xf2, 0xa3, 0x2d, 0xf2, 0xb1, 0x12, 0xdc, 0x21, 0x8e, 0xc7, 0xf6, 0xbf,
+		0x2f, 0xa0, 0x5e, 0x4b, 0x7e, 0x21, 0xd9, 0x1c, 0x25, 0x34, 0x7f, 0xfd,
+		0xd2, 0x88, 0x82, 0x99, 0xf5, 0x8b, 0xe4, 0x65, 0x55, 0x05, 0x6f, 0x39,
+		0x47, 0xbe, 0x6d, 0xf0, 0xcf, 0x37, 0xc2, 0x5d, 0x45, 0x98, 0x17, 0x89,
+		0x5f, 0x55, 0xc1, 0x07, 0x2c, 0xf4, 0xea, 0x71, 0x2b, 0xcf, 0x71, 0xf6,
+		0xa0, 0x75, 0x91, 0x01, 0xc1, 0x63, 0xef, 0x89, 0x0a, 0xdf, 0xb0, 0xf2,
+		0x84, 0xca, 0xb4, 0x07, 0xcd, 0x10, 0x14, 0x7b, 0x5c, 0xd8, 0x52, 0xb2,
+		0xf5, 0x8f, 0xa0, 0xb4, 0x1a, 0xb6, 0x78, 0x67, 0x93, 0x43, 0xaa, 0xa5,
+		0xa3, 0x18, 0x9f, 0x43, 0x33, 0x2d, 0xc6, 0x67, 0xaf, 0x5e, 0xfd, 0x3f,
+		0x89, 0x4a, 0xd3, 0x4c, 0x6d, 0x2e, 0x98, 0xce, 0x09, 0xb7, 0xfd, 0x12,
+		0xc1, 0xaa, 0x02, 0x54, 0xbc, 0xdf, 0x3d, 0xa3, 0xb0, 0x79, 0x64, 0x76,
+		0xa0, 0xe1, 0x73, 0x35, 0xda, 0xad, 0x9b, 0xc1, 0x2b, 0x0c, 0xb6, 0xa3,
+		0x47, 0x33, 0x3a, 0x6d, 0x67, 0x27, 0xb8, 0xeb, 0xcd, 0x52, 0x47, 0x26,
+		0xa0, 0x3b, 0x37, 0x70, 0xad, 0x1f, 0x1b, 0x80, 0xba, 0xcd, 0xe9, 0x38,
+		0x5b, 0xaa, 0xd4, 0x8d, 0xb8, 0xe3, 0x09, 0x54, 0x4e, 0x76, 0x3a, 0xf6,
+		0x82, 0x5e, 0x2b, 0x99, 0x04, 0xa9, 0x14, 0xe9, 0x7d, 0x0f, 0x56, 0x75,
+		0xcc, 0x4f, 0xc7, 0xa7, 0x63, 0xca, 0x85, 0x9d, 0x04, 0xae, 0xdd, 0x8c,
+		0xbd, 0xb6, 0xdf, 0x78, 0x93, 0xc9, 0x46, 0x7f, 0x3c, 0xf9, 0xc9, 0x41,
+		0xeb, 0xc9, 0xa8, 0x6e, 0x57, 0xd0, 0x27, 0x11, 0x85, 0xed, 0xa8, 0x15,
+		0x85, 0x39, 0x15, 0x32, 0x19, 0xfd, 0x33, 0x00, 0x15, 0x1f, 0xca, 0x36,
+		0x46, 0x0c, 0x00, 0x00,
 	},
 		"tmpl/index.html",
 	)
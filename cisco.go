@@ -2,9 +2,20 @@ package sweet
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+// the end of a privileged IOS/ASA prompt, from which learnPrompt finds the whole prompt
+var ciscoPromptEnd = regexp.MustCompile(`#\s*$`)
+
+// the end of an unprivileged prompt
+var ciscoUserPromptEnd = regexp.MustCompile(`>\s*$`)
+
+// Where a login ended up, matched on the last line so a "#" or ">" in a
+// banner doesn't count: privileged, unprivileged or asked for the password again.
+var ciscoLoginPrompts = []*regexp.Regexp{ciscoPromptEnd, ciscoUserPromptEnd, passwordPromptEnd}
+
 type Cisco struct {
 }
 
@@ -17,53 +28,69 @@ func (collector Cisco) Collect(device DeviceConfig) (map[string]string, error) {
 
 	c, err := newSSHCollector(device)
 	if err != nil {
-		return result, fmt.Errorf("Error connecting to %s: %s", device.Hostname, err.Error())
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
 	}
+	defer c.Close()
 
-	if err := expect("assword:", c.Receive); err != nil {
-		return result, fmt.Errorf("Missing password prompt: %s", err.Error())
+	if err := c.login(device); err != nil {
+		return result, err
 	}
-	c.Send <- device.Config["pass"] + "\n"
-	multi := []string{"#", ">", "assword:"}
-	m, err := expectMulti(multi, c.Receive)
+	m, _, err := expectMultiRegexp(ciscoLoginPrompts, c.Receive)
 	if err != nil {
 		return result, fmt.Errorf("Invalid response to password: %s", err.Error())
 	}
-	if m == "assword:" {
+	if ciscoLoginPrompts[m] == passwordPromptEnd {
 		return result, fmt.Errorf("Bad username or password.")
-	} else if m == ">" {
+	} else if ciscoLoginPrompts[m] == ciscoUserPromptEnd {
+		if len(device.Config["enable"]) == 0 {
+			return result, fmt.Errorf("Enable required but no enable or pass configured.")
+		}
 		c.Send <- "enable\n"
-		if err := expect("assword:", c.Receive); err != nil {
+		if err := expectRegexp(passwordPromptEnd, c.Receive); err != nil {
 			return result, fmt.Errorf("Missing enable password prompt: %s", err.Error())
 		}
 		c.Send <- device.Config["enable"] + "\n"
-		if err := expect("#", c.Receive); err != nil {
+		if err := expectRegexp(ciscoPromptEnd, c.Receive); err != nil {
 			return result, fmt.Errorf("Enable attempt failed: %s", err.Error())
 		}
 	}
-	c.Send <- "terminal length 0\n"
-	if err := expect("#", c.Receive); err != nil {
-		return result, fmt.Errorf("Command 'terminal length 0' failed: %s", err.Error())
+	prompt, err := c.learnPrompt(ciscoPromptEnd)
+	if err != nil {
+		return result, err
 	}
-	c.Send <- "terminal pager 0\n"
-	if err := expect("#", c.Receive); err != nil {
-		return result, fmt.Errorf("Command 'terminal pager 0' failed: %s", err.Error())
+	for _, command := range []string{"terminal length 0", "terminal pager 0"} {
+		c.Send <- command + "\n"
+		if err := expectRegexp(prompt, c.Receive); err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", command, err.Error())
+		}
 	}
-	c.Send <- "show running-config\n"
-	result["config"], err = expectSaveTimeout("#", c.Receive, device.CommandTimeout)
-	if err != nil {
-		return result, fmt.Errorf("Command 'show running-config' failed: %s", err.Error())
+	commands := []struct{ name, command string }{
+		{"config", "show running-config"},
+		{"version", "show version"},
 	}
-	c.Send <- "show version\n"
-	result["version"], err = expectSaveTimeout("#", c.Receive, device.CommandTimeout)
-	if err != nil {
-		return result, fmt.Errorf("Command 'show version' failed: %s", err.Error())
+	for _, cmd := range commands {
+		c.Send <- cmd.command + "\n"
+		output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+		if err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", cmd.command, err.Error())
+		}
+		result[cmd.name] = cleanCommandOutput(output, cmd.command)
 	}
 
 	// cleanup config results
-	result["config"] = strings.TrimSpace(strings.TrimPrefix(result["config"], "show running-config"))
 	result["config"] = strings.TrimSpace(strings.TrimPrefix(result["config"], "Building configuration..."))
 
+	if strings.Contains(result["version"], "Adaptive Security Appliance") {
+		if err := asaCollectFailover(c, device, prompt, result); err != nil {
+			return result, err
+		}
+	}
+	if device.Config["asa-contexts"] == "true" {
+		if err := asaCollectContexts(c, device, prompt, result); err != nil {
+			return result, err
+		}
+	}
+
 	c.Send <- "exit\n"
 
 	return result, nil
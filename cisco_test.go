@@ -32,7 +32,11 @@ func TestCiscoGood(t *testing.T) {
 
 	d.Target = d.Hostname
 
-	s := CollectCisco(*d)
+	s, err := newCiscoCollector().Collect(*d)
+	if err != nil {
+		t.Errorf("Collection failed: %s", err.Error())
+		return
+	}
 	if !strings.Contains(s["config"], "aaa authorization commands") {
 		t.Errorf("Config missing aaa line")
 	}
@@ -41,3 +45,31 @@ func TestCiscoGood(t *testing.T) {
 	}
 
 }
+
+func TestCiscoBannerWithHash(t *testing.T) {
+	shell := &fakeShell{
+		Banner: "##############################\r\n# Authorized access only #\r\n##############################\r\n",
+		Prompt: "router1>",
+		Responses: map[string]string{
+			"show running-config": "Building configuration...\r\n\r\nhostname router1\r\n",
+		},
+	}
+	shell.Hooks = map[string]func(){
+		"enable":   func() { shell.Prompt = "Password: " },
+		"enablepw": func() { shell.Prompt = "router1#" },
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Config["enable"] = "enablepw"
+
+	result, err := newCiscoCollector().Collect(d)
+	if err != nil {
+		t.Fatalf("Collection failed: %s", err.Error())
+	}
+	if shell.Received[0] != "enable" {
+		t.Errorf("A \"#\" in the banner should not skip enable: %v", shell.Received)
+	}
+	if !strings.HasPrefix(result["config"], "hostname router1") {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+}
@@ -4,6 +4,7 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"github.com/appliedtrust/sweet"
 	"github.com/docopt/docopt-go"
 	"github.com/vaughan0/go-ini"
@@ -11,6 +12,7 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -47,7 +49,9 @@ func main() {
 		Opts.LogFatal(err.Error())
 	}
 
-	//go sweet.RunWebserver(&Opts)
+	if Opts.HttpEnabled {
+		go sweet.RunWebserver(&Opts)
+	}
 
 	sweet.RunCollectors(&Opts)
 }
@@ -57,6 +61,7 @@ func setupOptions() (sweet.SweetOptions, error) {
 	Opts := sweet.SweetOptions{}
 	Opts.Status = &sweet.Status{}
 	Opts.Status.Status = make(map[string]sweet.DeviceStatus)
+	Opts.Profiles = make(map[string]*sweet.Profile)
 
 	arguments, err := docopt.Parse(usage, nil, true, version, false)
 	if err != nil {
@@ -172,6 +177,69 @@ func setupOptions() (sweet.SweetOptions, error) {
 			if ok {
 				Opts.DefaultMethod = defaultMethod
 			}
+			key, ok := section["key"]
+			if ok {
+				Opts.DefaultKey = key
+			}
+			keyPassphrase, ok := section["key-passphrase"]
+			if ok {
+				Opts.DefaultKeyPassphrase = keyPassphrase
+			}
+			boolText, ok = section["use-agent"]
+			if ok {
+				if boolText == "true" {
+					Opts.UseAgent = true
+				}
+			}
+			boolText, ok = section["debug-transcript"]
+			if ok {
+				if boolText == "true" {
+					Opts.DebugTranscript = true
+				}
+			}
+			ignoreRegex, ok := section["ignore-regex"]
+			if ok {
+				Opts.IgnoreRegex = ignoreRegex
+			}
+			redactRegex, ok := section["redact-regex"]
+			if ok {
+				Opts.RedactRegex = redactRegex
+			}
+			jumpHost, ok := section["jumphost"]
+			if ok {
+				Opts.JumpHost = jumpHost
+			}
+			jumpUser, ok := section["jumpuser"]
+			if ok {
+				Opts.JumpUser = jumpUser
+			}
+			jumpKey, ok := section["jumpkey"]
+			if ok {
+				Opts.JumpKey = jumpKey
+			}
+			profileDir, ok := section["profiles"]
+			if ok {
+				profiles, err := sweet.LoadProfileDir(profileDir)
+				if err != nil {
+					return Opts, err
+				}
+				for profileName, profile := range profiles {
+					if _, ok := Opts.Profiles[profileName]; ok {
+						return Opts, fmt.Errorf("Profile %s is defined more than once.", profileName)
+					}
+					Opts.Profiles[profileName] = profile
+				}
+			}
+
+		} else if strings.HasPrefix(name, sweet.ProfilePrefix) { // collector profile
+			profileName := strings.TrimPrefix(name, sweet.ProfilePrefix)
+			if _, ok := Opts.Profiles[profileName]; ok {
+				return Opts, fmt.Errorf("Profile %s is defined more than once.", profileName)
+			}
+			Opts.Profiles[profileName], err = sweet.LoadProfile(profileName, section)
+			if err != nil {
+				return Opts, err
+			}
 
 		} else { // device-specific config
 			device := sweet.DeviceConfig{Hostname: name, Method: section["method"], Config: section}
@@ -0,0 +1,73 @@
+package sweet
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type EOS struct {
+}
+
+func newEOSCollector() Collector {
+	return EOS{}
+}
+
+func (collector EOS) Collect(device DeviceConfig) (map[string]string, error) {
+	result := make(map[string]string)
+
+	c, err := newSSHCollector(device)
+	if err != nil {
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
+	}
+	defer c.Close()
+
+	if err := c.login(device); err != nil {
+		return result, err
+	}
+	m, _, err := expectMultiRegexp(ciscoLoginPrompts, c.Receive)
+	if err != nil {
+		return result, fmt.Errorf("Invalid response to password: %s", err.Error())
+	}
+	if ciscoLoginPrompts[m] == passwordPromptEnd {
+		return result, fmt.Errorf("Bad username or password.")
+	} else if ciscoLoginPrompts[m] == ciscoUserPromptEnd {
+		// EOS often has no enable password, so only answer if asked
+		c.Send <- "enable\n"
+		m, _, err = expectMultiRegexp([]*regexp.Regexp{ciscoPromptEnd, passwordPromptEnd}, c.Receive)
+		if err != nil {
+			return result, fmt.Errorf("Enable attempt failed: %s", err.Error())
+		}
+		if m == 1 {
+			c.Send <- device.Config["enable"] + "\n"
+			if err := expectRegexp(ciscoPromptEnd, c.Receive); err != nil {
+				return result, fmt.Errorf("Enable attempt failed: %s", err.Error())
+			}
+		}
+	}
+	prompt, err := c.learnPrompt(ciscoPromptEnd)
+	if err != nil {
+		return result, err
+	}
+	c.Send <- "terminal length 0\n"
+	if err := expectRegexp(prompt, c.Receive); err != nil {
+		return result, fmt.Errorf("Command 'terminal length 0' failed: %s", err.Error())
+	}
+
+	commands := []struct{ name, command string }{
+		{"config", "show running-config"},
+		{"version", "show version"},
+		{"inventory", "show inventory"},
+	}
+	for _, cmd := range commands {
+		c.Send <- cmd.command + "\n"
+		output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+		if err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", cmd.command, err.Error())
+		}
+		result[cmd.name] = cleanCommandOutput(output, cmd.command)
+	}
+
+	c.Send <- "exit\n"
+
+	return result, nil
+}
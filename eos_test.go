@@ -0,0 +1,42 @@
+package sweet
+
+import (
+	"testing"
+)
+
+func TestEOSCollect(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "leaf1>",
+		Responses: map[string]string{
+			"terminal length 0":   "Pagination disabled.\r\n",
+			"show running-config": "! Command: show running-config\r\nhostname leaf1\r\n!\r\nend\r\n",
+			"show version":        "Arista vEOS\r\nSoftware image version: 4.22.1F\r\n",
+			"show inventory":      "System information\r\n  Model: vEOS\r\n",
+		},
+	}
+	// switch to the privileged prompt once enabled
+	shell.Hooks = map[string]func(){"enable": func() { shell.Prompt = "leaf1#" }}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newEOSCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("EOS collection failed: %s", err.Error())
+	}
+	expected := map[string]string{
+		"config":    "! Command: show running-config\nhostname leaf1\n!\nend",
+		"version":   "Arista vEOS\nSoftware image version: 4.22.1F",
+		"inventory": "System information\n  Model: vEOS",
+	}
+	for name, want := range expected {
+		if result[name] != want {
+			t.Errorf("Bad %s result: %q", name, result[name])
+		}
+	}
+}
+
+func TestCleanCommandOutput(t *testing.T) {
+	out := cleanCommandOutput("show version\r\nline1\r\nline2\r\nrouter1#", "show version")
+	if out != "line1\nline2" {
+		t.Errorf("Bad cleaned output: %q", out)
+	}
+}
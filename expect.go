@@ -3,7 +3,8 @@ package sweet
 import (
 	"bytes"
 	"errors"
-	"os"
+	"io"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -31,7 +32,6 @@ func expectMulti(untilMulti []string, receive chan string) (string, error) {
 			}
 		}
 	}
-	return all, nil
 }
 
 // Save everything from channel until "until" string is matched
@@ -67,18 +67,133 @@ func expectSaveTimeout(until string, receive chan string, timeout time.Duration)
 			return all, nil
 		}
 	}
-	return all, nil
+}
+
+// Pager prompts like "--More--", "-- MORE --", "---(more 45%)---" and "Press any key to continue"
+const pagerMarker = `(?i)(-+ ?more ?-+|-+\(more[^)]*\)-+|<-+ ?more ?-+>|press any key to continue)`
+
+// The session answers a pager prompt with a space, wherever it appears.
+// The trailing text allows for e.g. "--More-- or (q)uit".
+var pagerPrompt = regexp.MustCompile(pagerMarker + `[^\r\n]*$`)
+
+// how much recent output to keep when looking for a pager prompt split across reads
+const pagerTailSize = 128
+
+// A pager prompt in saved output, which is always followed by whatever the
+// device used to wipe it off the screen: backspaces, a CR or an escape sequence
+var pagerArtefact = regexp.MustCompile(`[ \t]*` + pagerMarker + `[^\r\n\x08\x1b]*(\x08|\x1b|\r[^\n]|\r$)`)
+
+// Erasing that follows a pager prompt: "\r    \r" or "ESC[K", maybe with a CR
+var pagerErase = regexp.MustCompile(`\r[ \t]+\r|\x1b\[[0-9]*K\r?`)
+
+// Regexp expects match against the last line read so far, so a pattern
+// anchored with ^ and $ only matches a complete prompt at the end of the
+// output and never a "#" or ">" somewhere inside it.
+
+// Throw out everything from channel until "until" matches the last line
+func expectRegexp(until *regexp.Regexp, receive chan string) error {
+	_, _, err := expectMultiRegexp([]*regexp.Regexp{until}, receive)
+	return err
+}
+
+// Throw out everything from channel until one of "untilMulti" matches the last line, tell us which one and what was read
+func expectMultiRegexp(untilMulti []*regexp.Regexp, receive chan string) (int, string, error) {
+	all := ""
+	for {
+		select {
+		case s, exists := <-receive:
+			if !exists {
+				return -1, all, errors.New("Connection closed unexpectedly.")
+			}
+			all += s
+			line := lastLine(all)
+			for i, until := range untilMulti {
+				if until.MatchString(line) {
+					return i, all, nil
+				}
+			}
+		}
+	}
+}
+
+// Save everything from the channel until "until" matches the last line, with a read timeout
+func expectSaveRegexpTimeout(until *regexp.Regexp, receive chan string, timeout time.Duration) (string, error) {
+	all := ""
+	for {
+		select {
+		case s, exists := <-receive:
+			if !exists {
+				return "", errors.New("Connection closed unexpectedly.")
+			}
+			all += s
+			if until.MatchString(lastLine(all)) {
+				return all, nil
+			}
+		case _ = <-time.After(timeout):
+			return all, nil
+		}
+	}
+}
+
+// a password prompt at the end of the output
+var passwordPromptEnd = regexp.MustCompile(`assword:\s*$`)
+
+// Match exactly this prompt, allowing for trailing spaces
+func promptRegexp(prompt string) *regexp.Regexp {
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(strings.TrimSpace(prompt)) + `\s*$`)
+}
+
+// The final, possibly incomplete, line of output
+func lastLine(output string) string {
+	return output[strings.LastIndexAny(output, "\r\n")+1:]
 }
 
 // Read up to a full chunk from the session, removing nulls
-func readChunk(pty *os.File) (string, error) {
+func readChunk(r io.Reader) (string, error) {
 	chunk := make([]byte, 255)
-	n, err := pty.Read(chunk)
-	if err != nil {
-		return "", err
-	} else if n < 1 {
+	n, err := r.Read(chunk)
+	if n < 1 {
+		if err != nil {
+			return "", err
+		}
 		return "", errors.New("Read zero-length string")
 	}
-	chunk = bytes.Trim(chunk, "\x00")
+	chunk = bytes.Trim(chunk[:n], "\x00")
 	return string(chunk), nil
 }
+
+// Turn raw session output into plain text, then drop the echoed command from
+// its start and the prompt line from its end. Every interactive collector
+// passes its results through here.
+func cleanCommandOutput(output, command string) string {
+	output = renderTerminal(scrubPager(output))
+	output = strings.TrimLeft(output, "\r\n ")
+	output = strings.TrimPrefix(output, command)
+	if i := strings.LastIndex(output, "\n"); i >= 0 {
+		output = output[:i] // the prompt
+	}
+	return strings.Trim(output, "\r\n")
+}
+
+// Remove the pager prompts we paged through, and the backspaces and line
+// erasing the device used to wipe them from the screen
+func scrubPager(output string) string {
+	if !pagerArtefact.MatchString(output) {
+		return output
+	}
+	output = pagerArtefact.ReplaceAllString(output, "$2")
+	output = pagerErase.ReplaceAllString(output, "")
+
+	// apply backspaces, but never across a line break
+	scrubbed := make([]rune, 0, len(output))
+	for _, r := range output {
+		if r == '\x08' {
+			if n := len(scrubbed); n > 0 && scrubbed[n-1] != '\n' && scrubbed[n-1] != '\r' {
+				scrubbed = scrubbed[:n-1]
+			}
+			continue
+		}
+		scrubbed = append(scrubbed, r)
+	}
+	return string(scrubbed)
+}
@@ -4,11 +4,16 @@ import (
 	//"io/ioutil"
 	"os"
 	"os/exec"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 )
 
-const testTimeout = 10 * time.Microsecond
+// how long to wait for an expect call before deciding it never matched. It
+// only catches hangs, so it's well above goroutine start-up time: at 10µs the
+// tests failed at random whenever the scheduler was slow.
+const testTimeout = 100 * time.Millisecond
 
 func TestExpect(t *testing.T) {
 	c := make(chan string, 3)
@@ -208,3 +213,48 @@ func TestReadChunk(t *testing.T) {
 		t.Errorf("Unable to remove test file: %s", err.Error())
 	}
 }
+
+func TestExpectRegexpPrompt(t *testing.T) {
+	c := make(chan string, 4)
+	c <- "show running-config\r\nbanner motd ^C Authorized use only #"
+	c <- "1 ^C\r\ninterface Gi0/1\r\n description uplink to core-sw2#\r\n"
+	c <- "core-sw"
+	c <- "1#"
+	// everything is already buffered, so the read timeout only guards against a hang
+	saved, err := expectSaveRegexpTimeout(promptRegexp("core-sw1# "), c, time.Second)
+	if err != nil {
+		t.Fatalf("Error running expectSaveRegexpTimeout: %s", err.Error())
+	}
+	if !strings.HasSuffix(saved, "core-sw2#\r\ncore-sw1#") {
+		t.Errorf("Output ended at the wrong prompt: %q", saved)
+	}
+}
+
+func TestExpectMultiRegexp(t *testing.T) {
+	c := make(chan string, 2)
+	c <- "Last login: Tue # from 192.0.2.1\r\n"
+	c <- "router1>"
+	matched, output, err := expectMultiRegexp([]*regexp.Regexp{regexp.MustCompile(`#\s*$`), regexp.MustCompile(`>\s*$`)}, c)
+	if err != nil {
+		t.Fatalf("Error running expectMultiRegexp: %s", err.Error())
+	}
+	if matched != 1 || lastLine(output) != "router1>" {
+		t.Errorf("expectMultiRegexp matched %d at %q", matched, lastLine(output))
+	}
+}
+
+func TestScrubPager(t *testing.T) {
+	tests := map[string]string{
+		"vlan 1\r\n-- MORE --, next page: Space, next line: Enter, quit: Control-C\x1b[2K\r   name \"DEFAULT\"": "vlan 1\r\n   name \"DEFAULT\"",
+		"line1\r\nPress any key to continue (Q to quit)\r                                     \rline2":          "line1\r\nline2",
+		" description -- more -- later\r\n": " description -- more -- later\r\n",
+	}
+	for in, want := range tests {
+		if out := scrubPager(in); out != want {
+			t.Errorf("scrubPager(%q) = %q, want %q", in, out, want)
+		}
+	}
+	if !pagerPrompt.MatchString("interface Gi0/2\r\n --More-- or (q)uit") || pagerPrompt.MatchString("-- more --\r\nrouter#") {
+		t.Errorf("Bad pager prompt detection")
+	}
+}
@@ -0,0 +1,166 @@
+package sweet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FortiOS prompts look like "FGT60E # " or "FGT60E (dmz) # "
+var fortiOSPromptEnd = regexp.MustCompile(` #\s*$`)
+
+// "diagnose sys vd list" prints a "name=root/root index=0 ..." line per VDOM
+var fortiOSVDOMName = regexp.MustCompile(`^name=([^/\s]+)/`)
+
+type FortiOS struct {
+}
+
+func newFortiOSCollector() Collector {
+	return FortiOS{}
+}
+
+func (collector FortiOS) Collect(device DeviceConfig) (map[string]string, error) {
+	result := make(map[string]string)
+
+	c, err := newSSHCollector(device)
+	if err != nil {
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
+	}
+	defer c.Close()
+
+	if err := c.login(device); err != nil {
+		return result, err
+	}
+	m, output, err := expectMultiRegexp([]*regexp.Regexp{fortiOSPromptEnd, passwordPromptEnd}, c.Receive)
+	if err != nil {
+		return result, fmt.Errorf("Invalid response to password: %s", err.Error())
+	}
+	if m == 1 {
+		return result, fmt.Errorf("Bad username or password.")
+	}
+	prompt := fortiOSPromptRegexp(lastLine(output))
+
+	vdoms := len(device.Config["fortios-vdoms"]) > 0 && device.Config["fortios-vdoms"] != "false"
+	setup := []string{"config system console", "set output standard", "end"}
+	if vdoms {
+		// with VDOMs enabled the console settings and status live in the global scope
+		setup = append([]string{"config global"}, setup...)
+	}
+	for _, command := range setup {
+		if err := fortiOSRun(c, device, prompt, command, nil); err != nil {
+			return result, err
+		}
+	}
+
+	configCommand := "show full-configuration"
+	if vdoms {
+		configCommand = "show"
+	}
+	commands := []struct{ name, command string }{
+		{"config", configCommand},
+		{"version", "get system status"},
+	}
+	for _, cmd := range commands {
+		output := ""
+		if err := fortiOSRun(c, device, prompt, cmd.command, &output); err != nil {
+			return result, err
+		}
+		result[cmd.name] = fortiOSCleanOutput(output)
+	}
+
+	if vdoms {
+		names, err := fortiOSVDOMs(c, device, prompt)
+		if err != nil {
+			return result, err
+		}
+		if err := fortiOSRun(c, device, prompt, "end", nil); err != nil {
+			return result, err
+		}
+		for _, vdom := range names {
+			for _, command := range []string{"config vdom", "edit " + vdom} {
+				if err := fortiOSRun(c, device, prompt, command, nil); err != nil {
+					return result, err
+				}
+			}
+			output := ""
+			if err := fortiOSRun(c, device, prompt, "show", &output); err != nil {
+				return result, fmt.Errorf("In vdom %s: %s", vdom, err.Error())
+			}
+			result["vdom-"+vdom] = fortiOSCleanOutput(output)
+			if err := fortiOSRun(c, device, prompt, "end", nil); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	c.Send <- "exit\n"
+
+	return result, nil
+}
+
+// fortiOSRun sends one command and waits for the prompt, saving the output if asked to
+func fortiOSRun(c *SSHCollector, device DeviceConfig, prompt *regexp.Regexp, command string, output *string) error {
+	c.Send <- command + "\n"
+	if output == nil {
+		if err := expectRegexp(prompt, c.Receive); err != nil {
+			return fmt.Errorf("Command '%s' failed: %s", command, err.Error())
+		}
+		return nil
+	}
+	out, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+	if err != nil {
+		return fmt.Errorf("Command '%s' failed: %s", command, err.Error())
+	}
+	*output = cleanCommandOutput(out, command)
+	return nil
+}
+
+// fortiOSPromptRegexp matches the device's prompt in any config scope, from
+// the one it showed at login
+func fortiOSPromptRegexp(prompt string) *regexp.Regexp {
+	host := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(prompt), "#"))
+	if i := strings.Index(host, " ("); i >= 0 {
+		host = host[:i]
+	}
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(host) + `(?: \([^)]*\))? #\s*$`)
+}
+
+// fortiOSVDOMs lists the VDOMs to visit: either from the fortios-vdoms option,
+// or every VDOM the device reports when it is just "true".
+func fortiOSVDOMs(c *SSHCollector, device DeviceConfig, prompt *regexp.Regexp) ([]string, error) {
+	if device.Config["fortios-vdoms"] != "true" {
+		return splitList(device.Config["fortios-vdoms"]), nil
+	}
+	output := ""
+	if err := fortiOSRun(c, device, prompt, "diagnose sys vd list", &output); err != nil {
+		return nil, err
+	}
+	return parseFortiOSVDOMs(output), nil
+}
+
+// parseFortiOSVDOMs reads VDOM names from "diagnose sys vd list", skipping the internal vsys_ ones
+func parseFortiOSVDOMs(output string) []string {
+	vdoms := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		m := fortiOSVDOMName.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || strings.HasPrefix(m[1], "vsys_") {
+			continue
+		}
+		vdoms = append(vdoms, m[1])
+	}
+	return vdoms
+}
+
+// fortiOSCleanOutput drops the lines that change on every read: secrets the
+// device re-encrypts with a fresh salt, the config file revision and the clock.
+func fortiOSCleanOutput(output string) string {
+	lines := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.Contains(trimmed, " ENC ") || strings.HasPrefix(trimmed, "#conf_file_ver=") || strings.HasPrefix(trimmed, "System time:") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r "))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,110 @@
+package sweet
+
+import (
+	"strings"
+	"testing"
+)
+
+const fortiOSTestStatus = "Version: FortiGate-60E v6.4.5,build1828,210217 (GA)\r\n" +
+	"Virtual domain configuration: multiple\r\nSystem time: Sat Oct 18 10:00:00 2026\r\n"
+
+func TestFortiOSCollect(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "FGT60E # ",
+		Responses: map[string]string{
+			"show full-configuration": "#config-version=FGT60E-6.4.5-FW-build1828-210217:opmode=0:vdom=0\r\n" +
+				"#conf_file_ver=48151623420\r\nconfig system admin\r\n    edit \"admin\"\r\n" +
+				"        set password ENC SH2xyzzy\r\n    next\r\nend\r\n",
+			"get system status": "Version: FortiGate-60E v6.4.5,build1828,210217 (GA)\r\nSystem time: Sat Oct 18 10:00:00 2026\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newFortiOSCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("FortiOS collection failed: %s", err.Error())
+	}
+	expected := map[string]string{
+		"config":  "#config-version=FGT60E-6.4.5-FW-build1828-210217:opmode=0:vdom=0\nconfig system admin\n    edit \"admin\"\n    next\nend",
+		"version": "Version: FortiGate-60E v6.4.5,build1828,210217 (GA)",
+	}
+	for name, want := range expected {
+		if result[name] != want {
+			t.Errorf("Bad %s result: %q", name, result[name])
+		}
+	}
+	if shell.Received[0] != "config system console" || shell.Received[1] != "set output standard" {
+		t.Errorf("Paging was not disabled first: %v", shell.Received)
+	}
+}
+
+func TestFortiOSVDOMs(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "FGT60E # ",
+		Responses: map[string]string{
+			"get system status": fortiOSTestStatus,
+			"diagnose sys vd list": "system fib version=63\r\nlist virtual firewall info:\r\n" +
+				"name=vsys_ha/vsys_ha index=1 enabled fib_ver=3\r\n" +
+				"name=root/root index=0 enabled fib_ver=40\r\nname=dmz/dmz index=2 enabled fib_ver=7\r\n",
+		},
+	}
+	shell.Hooks = map[string]func(){
+		"config global": func() {
+			shell.Prompt = "FGT60E (global) # "
+			shell.Responses["show"] = "config system global\r\n    set hostname \"FGT60E\"\r\nend\r\n"
+		},
+		"edit root": func() {
+			shell.Prompt = "FGT60E (root) # "
+			shell.Responses["show"] = "config firewall policy\r\nend\r\n"
+		},
+		"edit dmz": func() {
+			shell.Prompt = "FGT60E (dmz) # "
+			shell.Responses["show"] = "config user local\r\n    edit \"guest\"\r\n        set passwd ENC AbCd\r\n    next\r\nend\r\n"
+		},
+		"end": func() { shell.Prompt = "FGT60E # " },
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Config["fortios-vdoms"] = "true"
+
+	result, err := newFortiOSCollector().Collect(d)
+	if err != nil {
+		t.Fatalf("FortiOS collection failed: %s", err.Error())
+	}
+	expected := map[string]string{
+		"config":    "config system global\n    set hostname \"FGT60E\"\nend",
+		"version":   "Version: FortiGate-60E v6.4.5,build1828,210217 (GA)\nVirtual domain configuration: multiple",
+		"vdom-root": "config firewall policy\nend",
+		"vdom-dmz":  "config user local\n    edit \"guest\"\n    next\nend",
+	}
+	for name, want := range expected {
+		if result[name] != want {
+			t.Errorf("Bad %s result: %q", name, result[name])
+		}
+	}
+	if _, ok := result["vdom-vsys_ha"]; ok {
+		t.Errorf("Internal vdom was collected")
+	}
+}
+
+func TestFortiOSPromptInConfig(t *testing.T) {
+	config := "config firewall policy\r\n    edit 1\r\n        set comments \"allow web # ticket 42\"\r\n" +
+		strings.Repeat("        set srcintf \"internal\"\r\n", 10) + "    next\r\nend\r\n"
+	shell := &fakeShell{
+		Prompt:    "FGT60E # ",
+		Responses: map[string]string{"show full-configuration": config},
+	}
+	shell.Hooks = map[string]func(){
+		"config system console": func() { shell.Prompt = "FGT60E (console) # " },
+		"end":                   func() { shell.Prompt = "FGT60E # " },
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newFortiOSCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("FortiOS collection failed: %s", err.Error())
+	}
+	if result["config"] != strings.TrimSpace(strings.Replace(config, "\r\n", "\n", -1)) {
+		t.Errorf("Config cut short at a prompt-like line: %q", result["config"])
+	}
+}
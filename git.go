@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 )
 
 //
@@ -89,3 +90,42 @@ func updateDiffs(Opts *SweetOptions) error {
 	}
 	return nil
 }
+
+// record when each collected device's results were last committed, once per
+// run, logging the devices it can't look up
+func updateLastChanged(Opts *SweetOptions) {
+	for _, device := range Opts.Devices {
+		stat := Opts.Status.Get(device.Hostname)
+		if len(stat.Configs) == 0 {
+			continue // keep what we knew before this run
+		}
+		files := make([]string, 0, len(stat.Configs))
+		for name := range stat.Configs {
+			files = append(files, device.Hostname+"-"+cleanName(name))
+		}
+		changed, err := lastChanged(files)
+		if err != nil {
+			Opts.LogErr(fmt.Sprintf("Unable to find last change for %s: %s", device.Hostname, err.Error()))
+			continue
+		}
+		stat.LastChanged = changed
+		Opts.Status.Set(stat)
+	}
+}
+
+// find when any of these workspace files was last committed
+func lastChanged(files []string) (time.Time, error) {
+	out, err := exec.Command("git", append([]string{"log", "-1", "--format=%ct", "--"}, files...)...).Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+	secs := strings.TrimSpace(string(out))
+	if len(secs) == 0 {
+		return time.Time{}, nil // never committed
+	}
+	unix, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(unix, 0), nil
+}
@@ -0,0 +1,56 @@
+package sweet
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// gitCommitAt commits a workspace file with a fixed commit time
+func gitCommitAt(t *testing.T, file, content string, when time.Time) {
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Error writing %s: %s", file, err.Error())
+	}
+	for _, args := range [][]string{
+		{"add", file},
+		{"-c", "user.name=sweet", "-c", "user.email=sweet@example.com", "commit", "-q", "-m", "Sweet commit", file},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+when.Format(time.RFC3339))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %s", args, out)
+		}
+	}
+}
+
+func TestGitUpdateLastChanged(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	inTempWorkspace(t)
+	if out, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %s", out)
+	}
+	sw1Changed := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	gitCommitAt(t, "sw1-config", "hostname sw1", sw1Changed)
+	gitCommitAt(t, "sw1-core-config", "hostname sw1-core", sw1Changed.Add(24*time.Hour))
+
+	// git rejects the pathspec magic in this name, which shouldn't stop the devices after it
+	bad := DeviceConfig{Hostname: ":(bad)"}
+	sw1 := DeviceConfig{Hostname: "sw1"}
+	sw2 := DeviceConfig{Hostname: "sw2"}
+	Opts := &SweetOptions{Devices: []DeviceConfig{bad, sw1, sw2}, Status: &Status{Status: make(map[string]DeviceStatus)}}
+	Opts.Status.Set(DeviceStatus{Device: bad, State: StateSuccess, Configs: map[string]string{"config": "hostname bad"}})
+	Opts.Status.Set(DeviceStatus{Device: sw1, State: StateSuccess, Configs: map[string]string{"config": "hostname sw1"}})
+	Opts.Status.Set(DeviceStatus{Device: sw2, State: StateError, LastChanged: sw1Changed})
+
+	updateLastChanged(Opts)
+	if changed := Opts.Status.Get("sw1").LastChanged; !changed.Equal(sw1Changed) {
+		t.Errorf("sw1 should not pick up sw1-core's change: %s", changed)
+	}
+	if changed := Opts.Status.Get("sw2").LastChanged; !changed.Equal(sw1Changed) {
+		t.Errorf("A failed collection should keep its last change: %s", changed)
+	}
+}
@@ -0,0 +1,136 @@
+package sweet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// VT100 cursor, erase and mode sequences as well as charset selection
+var hpEscape = regexp.MustCompile(`\x1b(\[[0-9;?]*[A-Za-z]|[()][A-Z0-9]|[A-Za-z=<>])`)
+
+// HP login outcomes, matched on the last line and allowing for the cursor
+// escapes ProCurve draws after its prompts
+var (
+	hpPromptEnd      = regexp.MustCompile(`#(\s|` + hpEscape.String() + `)*$`)
+	hpUserPromptEnd  = regexp.MustCompile(`>(\s|` + hpEscape.String() + `)*$`)
+	hpUsernamePrompt = regexp.MustCompile(`sername:\s*$`)
+	hpBanner         = regexp.MustCompile(`Press any key`)
+)
+
+type ProCurve struct {
+}
+
+type AOSCX struct {
+}
+
+func newProCurveCollector() Collector {
+	return ProCurve{}
+}
+
+func newAOSCXCollector() Collector {
+	return AOSCX{}
+}
+
+func (collector ProCurve) Collect(device DeviceConfig) (map[string]string, error) {
+	return hpCollect(device, true)
+}
+
+func (collector AOSCX) Collect(device DeviceConfig) (map[string]string, error) {
+	return hpCollect(device, false)
+}
+
+// hpCollect drives both HP CLIs, which differ mainly in ProCurve's login banner
+func hpCollect(device DeviceConfig, banner bool) (map[string]string, error) {
+	result := make(map[string]string)
+
+	c, err := newSSHCollector(device)
+	if err != nil {
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
+	}
+	defer c.Close()
+
+	if err := c.login(device); err != nil {
+		return result, err
+	}
+	prompts := []*regexp.Regexp{hpPromptEnd, hpUserPromptEnd, passwordPromptEnd}
+	if banner {
+		prompts = append([]*regexp.Regexp{hpBanner}, prompts...)
+	}
+	m, _, err := expectMultiRegexp(prompts, c.Receive)
+	if err != nil {
+		return result, fmt.Errorf("Invalid response to password: %s", err.Error())
+	}
+	if prompts[m] == hpBanner {
+		c.Send <- "\n"
+		prompts = prompts[1:]
+		m, _, err = expectMultiRegexp(prompts, c.Receive)
+		if err != nil {
+			return result, fmt.Errorf("No prompt after login banner: %s", err.Error())
+		}
+	}
+	if prompts[m] == passwordPromptEnd {
+		return result, fmt.Errorf("Bad username or password.")
+	} else if prompts[m] == hpUserPromptEnd {
+		c.Send <- "enable\n"
+		enablePrompts := []*regexp.Regexp{hpPromptEnd, hpUsernamePrompt, passwordPromptEnd}
+		m, _, err = expectMultiRegexp(enablePrompts, c.Receive)
+		if err != nil {
+			return result, fmt.Errorf("Enable attempt failed: %s", err.Error())
+		}
+		asked := enablePrompts[m]
+		if asked == hpUsernamePrompt {
+			c.Send <- device.Config["user"] + "\n"
+			if err := expectRegexp(passwordPromptEnd, c.Receive); err != nil {
+				return result, fmt.Errorf("Enable attempt failed: %s", err.Error())
+			}
+			asked = passwordPromptEnd
+		}
+		if asked == passwordPromptEnd {
+			c.Send <- device.Config["enable"] + "\n"
+			if err := expectRegexp(hpPromptEnd, c.Receive); err != nil {
+				return result, fmt.Errorf("Enable attempt failed: %s", err.Error())
+			}
+		}
+	}
+	prompt, err := hpLearnPrompt(c)
+	if err != nil {
+		return result, err
+	}
+	c.Send <- "no page\n"
+	if err := expectRegexp(prompt, c.Receive); err != nil {
+		return result, fmt.Errorf("Command 'no page' failed: %s", err.Error())
+	}
+
+	commands := []struct{ name, command string }{
+		{"config", "show running-config"},
+		{"system", "show system"},
+	}
+	for _, cmd := range commands {
+		c.Send <- cmd.command + "\n"
+		output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+		if err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", cmd.command, err.Error())
+		}
+		output = cleanCommandOutput(output, cmd.command)
+		result[cmd.name] = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(output), "Running configuration:"))
+	}
+
+	c.Send <- "exit\n"
+
+	return result, nil
+}
+
+// hpLearnPrompt learns the exact prompt like learnPrompt does, but lets it be
+// wrapped in the cursor positioning ProCurve draws it with (and which can
+// stand in for a line break before it).
+func hpLearnPrompt(c *SSHCollector) (*regexp.Regexp, error) {
+	c.Send <- "\n"
+	_, output, err := expectMultiRegexp([]*regexp.Regexp{hpPromptEnd}, c.Receive)
+	if err != nil {
+		return nil, fmt.Errorf("Missing prompt: %s", err.Error())
+	}
+	prompt := strings.TrimSpace(hpEscape.ReplaceAllString(lastLine(output), ""))
+	escape := hpEscape.String()
+	return regexp.MustCompile(`(^|` + escape + `)` + regexp.QuoteMeta(prompt) + `(\s|` + escape + `)*$`), nil
+}
@@ -0,0 +1,68 @@
+package sweet
+
+import (
+	"testing"
+)
+
+func TestProCurveCollect(t *testing.T) {
+	shell := &fakeShell{
+		Banner: "\x1b[2J\x1b[?25l\x1b[1;1HHP J9773A 2530-24G-PoEP Switch\r\nSoftware revision YA.16.02\r\n\r\nPress any key to continue\x1b[?25h",
+		Responses: map[string]string{
+			"show running-config": "\x1b[2K\r\nRunning configuration:\r\n\r\n; J9773A Configuration Editor; Created on release #YA.16.02.0012\r\n" +
+				"hostname \"sw1\"\r\nvlan 1\r\n   name \"DEFAULT_VLAN\"\r\n   exit\r\n",
+			"show system": "\x1b[1;24r\x1b[24;1H\r\n Status and Counters - General System Information\r\n\r\n  System Name        : sw1\r\n",
+		},
+	}
+	// the prompt only appears once a key has been pressed
+	shell.Hooks = map[string]func(){
+		"": func() {
+			if shell.Prompt == "" {
+				shell.Prompt = "\x1b[24;1Hsw1> "
+			}
+		},
+		"enable": func() { shell.Prompt = "\x1b[24;1Hsw1# " },
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newProCurveCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("ProCurve collection failed: %s", err.Error())
+	}
+	expected := map[string]string{
+		"config": "; J9773A Configuration Editor; Created on release #YA.16.02.0012\nhostname \"sw1\"\nvlan 1\n   name \"DEFAULT_VLAN\"\n   exit",
+		"system": "Status and Counters - General System Information\n\n  System Name        : sw1",
+	}
+	for name, want := range expected {
+		if result[name] != want {
+			t.Errorf("Bad %s result: %q", name, result[name])
+		}
+	}
+	if shell.Received[0] != "" || shell.Received[1] != "enable" {
+		t.Errorf("The banner should be answered with a single keypress: %q", shell.Received)
+	}
+}
+
+func TestAOSCXCollect(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "sw2# ",
+		Responses: map[string]string{
+			"show running-config": "Current configuration:\r\n!\r\n!Version ArubaOS-CX FL.10.06.0110\r\nhostname sw2\r\n",
+			"show system":         "Hostname           : sw2\r\nProduct Name       : JL658A 6300M\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newAOSCXCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("AOS-CX collection failed: %s", err.Error())
+	}
+	if result["config"] != "Current configuration:\n!\n!Version ArubaOS-CX FL.10.06.0110\nhostname sw2" {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+	if result["system"] != "Hostname           : sw2\nProduct Name       : JL658A 6300M" {
+		t.Errorf("Bad system result: %q", result["system"])
+	}
+	if shell.Received[1] != "no page" {
+		t.Errorf("Paging was not disabled first: %v", shell.Received)
+	}
+}
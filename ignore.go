@@ -0,0 +1,157 @@
+package sweet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// volatileLines are built-in rules for lines that change on every run without
+// the device changing, e.g. timestamps in config headers and uptime counters.
+// They are kept to the result they appear in, so e.g. an interface description
+// mentioning "uptime is" survives in the config.
+var volatileLines = map[string]map[string][]*regexp.Regexp{
+	"cisco": {
+		"config":  ciscoConfigLines,
+		"context": ciscoConfigLines, // ASA security contexts
+		"version": {
+			regexp.MustCompile(` uptime is `),
+			regexp.MustCompile(`^\S+ up \d+ (years?|days?|hours?|mins?|secs?)`), // ASA
+			regexp.MustCompile(`^failover cluster up \d+ `),
+		},
+	},
+	"iosxr": {
+		"config": {
+			regexp.MustCompile(`^!! Last configuration change at `),
+		},
+		"version": {
+			regexp.MustCompile(` uptime is `),
+		},
+	},
+	"nxos": {
+		"config": {
+			regexp.MustCompile(`^!Time: `),
+			regexp.MustCompile(`^!Running configuration last done at: `),
+			regexp.MustCompile(`^!Startup config saved at: `),
+		},
+		"version": {
+			regexp.MustCompile(`^\s*Kernel uptime is `),
+			regexp.MustCompile(`^\s*Last reset `),
+		},
+	},
+	"eos": {
+		"config": {
+			regexp.MustCompile(`^! Startup-config last modified at `),
+		},
+		"version": {
+			regexp.MustCompile(`^Uptime: `),
+			regexp.MustCompile(`^Free memory: `),
+		},
+	},
+	"junos": {
+		"config": {
+			regexp.MustCompile(`^## Last commit: `),
+			regexp.MustCompile(`<junos:comment>## Last commit: `),
+		},
+	},
+	"routeros": {
+		"version": {
+			regexp.MustCompile(`^\s*(uptime|free-memory|free-hdd-space|cpu-load|write-sect-since-reboot|write-sect-total|bad-blocks):`),
+		},
+	},
+	"panos": {
+		"version": {
+			regexp.MustCompile(`^\s*<(uptime|time)>.*</(uptime|time)>\s*$`),
+		},
+	},
+	"procurve": {
+		"system": {
+			regexp.MustCompile(`^\s*Up Time\s*:`),
+			regexp.MustCompile(`^\s*CPU Util `),
+			regexp.MustCompile(`^\s*Packet\s+- Total\s*:`),
+		},
+	},
+	"aoscx": {
+		"system": {
+			regexp.MustCompile(`^\s*Up Time\s*:`),
+			regexp.MustCompile(`^\s*CPU Util `),
+			regexp.MustCompile(`^\s*Memory Usage `),
+		},
+	},
+}
+
+var ciscoConfigLines = []*regexp.Regexp{
+	regexp.MustCompile(`^! Last configuration change at `),
+	regexp.MustCompile(`^! NVRAM config last updated at `),
+	regexp.MustCompile(`^! No configuration change since last restart`),
+	regexp.MustCompile(`^ntp clock-period `),
+	regexp.MustCompile(`^Cryptochecksum:`), // ASA
+	regexp.MustCompile(`^: Written by `),
+}
+
+// volatileResult tells whether a result name is one of kind's, allowing for
+// the prefixes and suffixes collectors add, e.g. "vdc-core-version",
+// "startup-config", "config-set" and "context-admin".
+func volatileResult(name, kind string) bool {
+	return name == kind || strings.HasPrefix(name, kind+"-") || strings.HasSuffix(name, "-"+kind)
+}
+
+// ignoreFilter drops volatile lines from a device's results
+type ignoreFilter struct {
+	volatile map[string][]*regexp.Regexp // by result kind
+	custom   []*regexp.Regexp            // from ignore-regex, for every result
+}
+
+//// Gather the built-in rules for the device's method and any ignore-regex settings, global first
+func newIgnoreFilter(device DeviceConfig, Opts *SweetOptions) (*ignoreFilter, error) {
+	f := &ignoreFilter{volatile: volatileLines[device.Method]}
+	for _, pattern := range []string{Opts.IgnoreRegex, device.Config["ignore-regex"]} {
+		if len(pattern) == 0 {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Bad ignore-regex setting %s for host %s: %s", pattern, device.Hostname, err.Error())
+		}
+		f.custom = append(f.custom, re)
+	}
+	return f, nil
+}
+
+// rules lists the rules that apply to the named result
+func (f *ignoreFilter) rules(name string) []*regexp.Regexp {
+	rules := append([]*regexp.Regexp{}, f.custom...)
+	for kind, volatile := range f.volatile {
+		if volatileResult(name, kind) {
+			rules = append(rules, volatile...)
+		}
+	}
+	return rules
+}
+
+// filter drops the volatile lines from one of the device's results
+func (f *ignoreFilter) filter(name, output string) string {
+	return ignoreLines(output, f.rules(name))
+}
+
+// ignoreLines drops every line of a collection result matched by a rule
+func ignoreLines(output string, rules []*regexp.Regexp) string {
+	if len(rules) == 0 {
+		return output
+	}
+	lines := strings.Split(output, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		ignored := false
+		for _, re := range rules {
+			if re.MatchString(strings.TrimRight(line, "\r")) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,93 @@
+package sweet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIgnoreCiscoVolatileLines(t *testing.T) {
+	device := DeviceConfig{Hostname: "sw1", Method: "cisco", Config: map[string]string{}}
+	ignore, err := newIgnoreFilter(device, &SweetOptions{})
+	if err != nil {
+		t.Fatalf("Error building rules: %s", err.Error())
+	}
+	config := "!\r\n! Last configuration change at 10:01:02 UTC Mon Oct 18 2026 by admin\r\n! NVRAM config last updated at 09:00:00 UTC Mon Oct 18 2026\r\nhostname sw1\r\nntp clock-period 36028797\r\nntp server 10.0.0.1"
+	expected := "!\r\nhostname sw1\r\nntp server 10.0.0.1"
+	if result := ignore.filter("config", config); result != expected {
+		t.Errorf("Bad filtered config: %q", result)
+	}
+	version := "Cisco IOS Software\nsw1 uptime is 2 weeks, 3 days\nSystem image file is \"flash:c2960.bin\""
+	if result := ignore.filter("version", version); strings.Contains(result, "uptime") || !strings.Contains(result, "System image") {
+		t.Errorf("Bad filtered version: %q", result)
+	}
+}
+
+func TestIgnoreScopedToResult(t *testing.T) {
+	device := DeviceConfig{Hostname: "sw1", Method: "cisco", Config: map[string]string{}}
+	ignore, _ := newIgnoreFilter(device, &SweetOptions{})
+	config := "interface Gi0/1\n description alert if uptime is low\n"
+	if result := ignore.filter("config", config); result != config {
+		t.Errorf("Version rules should not touch the config: %q", result)
+	}
+
+	device.Method = "nxos"
+	ignore, _ = newIgnoreFilter(device, &SweetOptions{})
+	for name, output := range map[string]string{
+		"vdc-core-config":  "!Time: Mon Oct 18 10:01:02 2026\nhostname core",
+		"startup-config":   "!Startup config saved at: Mon Oct 18 10:01:02 2026\nhostname core",
+		"vdc-core-version": "Kernel uptime is 3 day(s)\nhostname core",
+	} {
+		if result := ignore.filter(name, output); result != "hostname core" {
+			t.Errorf("Bad filtered %s: %q", name, result)
+		}
+	}
+}
+
+func TestIgnoreASAContexts(t *testing.T) {
+	device := DeviceConfig{Hostname: "asa", Method: "cisco", Config: map[string]string{}}
+	ignore, _ := newIgnoreFilter(device, &SweetOptions{})
+	config := ": Saved\n: Written by enable_15 at 10:01:02.123 UTC Mon Oct 18 2026\nhostname dmz\nCryptochecksum:0123456789abcdef0123456789abcdef"
+	for _, name := range []string{"config", "context-dmz"} {
+		if result := ignore.filter(name, config); result != ": Saved\nhostname dmz" {
+			t.Errorf("Bad filtered %s: %q", name, result)
+		}
+	}
+}
+
+func TestIgnoreOtherMethods(t *testing.T) {
+	cases := []struct{ method, name, output, expected string }{
+		{"junos", "config", "## Last commit: 2026-10-18 10:01:02 UTC by admin\nversion 20.4R3;", "version 20.4R3;"},
+		{"junos", "config-xml", "    <junos:comment>## Last commit: 2026-10-18 10:01:02 UTC by admin</junos:comment>\n    <version>20.4R3</version>", "    <version>20.4R3</version>"},
+		{"nxos", "config", "!Command: show running-config\n!Time: Mon Oct 18 10:01:02 2026\nhostname n1", "!Command: show running-config\nhostname n1"},
+		{"routeros", "version", "                   uptime: 1w2d3h\n                  version: 7.1\n              free-memory: 90.1MiB", "                  version: 7.1"},
+		{"panos", "version", "<system>\n  <time>Mon Oct 18 10:01:02 2026</time>\n  <uptime>3 days, 1:02:03</uptime>\n  <sw-version>10.1.0</sw-version>\n</system>", "<system>\n  <sw-version>10.1.0</sw-version>\n</system>"},
+		{"procurve", "system", "  System Name        : sw1\n  Up Time            : 12 days        Memory   - Total   : 152,455,168\n  CPU Util (%)       : 3              Free : 96,262,376", "  System Name        : sw1"},
+		{"cisco", "version", "failover cluster up 3 days 2 hours\nHardware:   ASA5516", "Hardware:   ASA5516"},
+		{"linux", "command-uptime", "up 3 days\n", "up 3 days\n"},
+	}
+	for _, c := range cases {
+		device := DeviceConfig{Hostname: "dev1", Method: c.method, Config: map[string]string{}}
+		ignore, _ := newIgnoreFilter(device, &SweetOptions{})
+		if result := ignore.filter(c.name, c.output); result != c.expected {
+			t.Errorf("Bad %s %s filtering: %q", c.method, c.name, result)
+		}
+	}
+}
+
+func TestIgnoreRegexSettings(t *testing.T) {
+	device := DeviceConfig{Hostname: "dev1", Method: "external", Config: map[string]string{"ignore-regex": "^Generated "}}
+	Opts := &SweetOptions{IgnoreRegex: `^\s*Temperature`}
+	ignore, err := newIgnoreFilter(device, Opts)
+	if err != nil {
+		t.Fatalf("Error building rules: %s", err.Error())
+	}
+	output := "Generated at 10:01\nhostname lb1\n  Temperature: 41C\nvlan 10"
+	if result := ignore.filter("config", output); result != "hostname lb1\nvlan 10" {
+		t.Errorf("Bad filtered output: %q", result)
+	}
+
+	device.Config["ignore-regex"] = "(unclosed"
+	if _, err := newIgnoreFilter(device, Opts); err == nil || !strings.Contains(err.Error(), "Bad ignore-regex setting") {
+		t.Errorf("Expected a bad ignore-regex error but got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package sweet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IOS-XR prints e.g. "Mon Oct 12 10:01:02.123 UTC" before every command's output
+var iosxrTimestamp = regexp.MustCompile(`^(Mon|Tue|Wed|Thu|Fri|Sat|Sun) (Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) +\d+ \d+:\d+:\d+(\.\d+)? \S+$`)
+
+type IOSXR struct {
+}
+
+func newIOSXRCollector() Collector {
+	return IOSXR{}
+}
+
+func (collector IOSXR) Collect(device DeviceConfig) (map[string]string, error) {
+	result := make(map[string]string)
+
+	c, err := newSSHCollector(device)
+	if err != nil {
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
+	}
+	defer c.Close()
+
+	if err := c.login(device); err != nil {
+		return result, err
+	}
+	m, _, err := expectMultiRegexp([]*regexp.Regexp{ciscoPromptEnd, passwordPromptEnd}, c.Receive)
+	if err != nil {
+		return result, fmt.Errorf("Invalid response to password: %s", err.Error())
+	}
+	if m == 1 {
+		return result, fmt.Errorf("Bad username or password.")
+	}
+
+	// learn the full prompt (e.g. "RP/0/RSP0/CPU0:host#") so a "#" in the output doesn't end it early
+	prompt, err := c.learnPrompt(ciscoPromptEnd)
+	if err != nil {
+		return result, err
+	}
+
+	for _, command := range []string{"terminal length 0", "terminal width 512"} {
+		c.Send <- command + "\n"
+		if err := expectRegexp(prompt, c.Receive); err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", command, err.Error())
+		}
+	}
+
+	commands := []struct{ name, command string }{
+		{"config", "show running-config"},
+		{"version", "show version"},
+	}
+	for _, cmd := range commands {
+		c.Send <- cmd.command + "\n"
+		output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+		if err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", cmd.command, err.Error())
+		}
+		result[cmd.name] = iosxrCleanOutput(cleanCommandOutput(output, cmd.command))
+	}
+
+	c.Send <- "exit\n"
+
+	return result, nil
+}
+
+// iosxrCleanOutput removes the per-command timestamp and the "Building configuration..." line
+func iosxrCleanOutput(output string) string {
+	output = strings.TrimLeft(output, "\n")
+	if i := strings.Index(output, "\n"); i >= 0 && iosxrTimestamp.MatchString(strings.TrimSpace(output[:i])) {
+		output = output[i+1:]
+	} else if iosxrTimestamp.MatchString(strings.TrimSpace(output)) {
+		output = ""
+	}
+	output = strings.TrimPrefix(output, "Building configuration...\n")
+	return strings.TrimSpace(output)
+}
@@ -0,0 +1,30 @@
+package sweet
+
+import (
+	"testing"
+)
+
+func TestIOSXRCollect(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "RP/0/RSP0/CPU0:xr1#",
+		Responses: map[string]string{
+			"terminal length 0":   "Mon Oct 12 10:01:01.001 UTC\r\n",
+			"terminal width 512":  "Mon Oct 12 10:01:01.002 UTC\r\n",
+			"show running-config": "Mon Oct 12 10:01:02.123 UTC\r\nBuilding configuration...\r\n!! IOS XR Configuration 6.1.3\r\n!\r\nhostname xr1\r\ninterface Loopback0\r\n description uplink #1\r\n!\r\nend\r\n\r\n",
+			"show version":        "Mon Oct 12 10:01:03.456 UTC\r\n\r\nCisco IOS XR Software, Version 6.1.3[Default]\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newIOSXRCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("IOS-XR collection failed: %s", err.Error())
+	}
+	want := "!! IOS XR Configuration 6.1.3\n!\nhostname xr1\ninterface Loopback0\n description uplink #1\n!\nend"
+	if result["config"] != want {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+	if result["version"] != "Cisco IOS XR Software, Version 6.1.3[Default]" {
+		t.Errorf("Bad version result: %q", result["version"])
+	}
+}
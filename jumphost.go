@@ -0,0 +1,117 @@
+package sweet
+
+import (
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"net"
+	"strings"
+)
+
+// jumpHop is one bastion on the way to a device.
+type jumpHop struct {
+	User string
+	Host string
+	Port string
+}
+
+func (h jumpHop) String() string {
+	return h.Host
+}
+
+// parseJumpHosts reads a "jumphost" list like "bastion1, admin@bastion2:2222".
+func parseJumpHosts(device DeviceConfig) []jumpHop {
+	hops := []jumpHop{}
+	if device.Config["jumphost"] == "none" {
+		return hops
+	}
+	for _, spec := range splitList(device.Config["jumphost"]) {
+		hop := jumpHop{User: device.Config["jumpuser"], Host: spec, Port: "22"}
+		if i := strings.LastIndex(hop.Host, "@"); i >= 0 {
+			hop.User = hop.Host[:i]
+			hop.Host = hop.Host[i+1:]
+		}
+		if host, port, err := net.SplitHostPort(hop.Host); err == nil {
+			hop.Host = host
+			hop.Port = port
+		}
+		if len(hop.User) == 0 {
+			hop.User = device.Config["user"]
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
+
+// jumpHopDevice builds the login settings for a hop from the device's jump options
+func jumpHopDevice(device DeviceConfig, hop jumpHop) DeviceConfig {
+	d := DeviceConfig{Hostname: hop.Host, Target: hop.Host, Timeout: device.Timeout}
+	d.Config = map[string]string{
+		"user":        hop.User,
+		"pass":        device.Config["jumppass"],
+		"port":        hop.Port,
+		"key":         device.Config["key"],
+		"use-agent":   device.Config["use-agent"],
+		"insecure":    device.Config["insecure"],
+		"known-hosts": device.Config["known-hosts"],
+	}
+	if len(device.Config["jumpkey"]) > 0 {
+		d.Config["key"] = device.Config["jumpkey"]
+	}
+	if d.Config["key"] == device.Config["key"] {
+		d.Config["key-passphrase"] = device.Config["key-passphrase"]
+	}
+	return d
+}
+
+//// Connect to addr, tunnelling through the device's jump hosts if it has any
+func dialDevice(device DeviceConfig, addr string) (net.Conn, []io.Closer, error) {
+	hops := parseJumpHosts(device)
+	if len(hops) == 0 {
+		conn, err := net.DialTimeout("tcp", addr, device.Timeout)
+		return conn, nil, err
+	}
+
+	closers := []io.Closer{}
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i].Close()
+		}
+	}
+	var client *ssh.Client
+	for i, hop := range hops {
+		hopDevice := jumpHopDevice(device, hop)
+		hopAddr := sshAddress(hopDevice)
+		config, agentConn, err := sshClientConfig(hopDevice)
+		if err == nil {
+			var conn net.Conn
+			if client == nil {
+				conn, err = net.DialTimeout("tcp", hopAddr, device.Timeout)
+			} else {
+				conn, err = client.Dial("tcp", hopAddr)
+			}
+			if err == nil {
+				client, err = sshHandshake(conn, hopAddr, config, device.Timeout)
+			}
+		}
+		if agentConn != nil {
+			agentConn.Close()
+		}
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("jump host %s (hop %d of %d) failed: %w", hop, i+1, len(hops), err)
+		}
+		closers = append(closers, client)
+	}
+
+	conn, err := client.Dial("tcp", addr)
+	if err != nil {
+		closeAll()
+		return nil, nil, fmt.Errorf("jump host %s could not reach %s: %s", hops[len(hops)-1], addr, err.Error())
+	}
+	// innermost first, so Close tears the tunnel down from the device outwards
+	for i, j := 0, len(closers)-1; i < j; i, j = i+1, j-1 {
+		closers[i], closers[j] = closers[j], closers[i]
+	}
+	return conn, closers, nil
+}
@@ -0,0 +1,80 @@
+package sweet
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestJumpHostParse(t *testing.T) {
+	d := DeviceConfig{Config: map[string]string{
+		"user":     "sweet",
+		"jumpuser": "jumper",
+		"jumphost": "bastion1, admin@bastion2:2222",
+	}}
+	hops := parseJumpHosts(d)
+	if len(hops) != 2 {
+		t.Fatalf("Expected 2 hops but got %d", len(hops))
+	}
+	if hops[0] != (jumpHop{User: "jumper", Host: "bastion1", Port: "22"}) {
+		t.Errorf("Bad first hop: %+v", hops[0])
+	}
+	if hops[1] != (jumpHop{User: "admin", Host: "bastion2", Port: "2222"}) {
+		t.Errorf("Bad second hop: %+v", hops[1])
+	}
+
+	d.Config["jumphost"] = "none"
+	if hops := parseJumpHosts(d); len(hops) != 0 {
+		t.Errorf("jumphost = none should disable jumping: %v", hops)
+	}
+
+	delete(d.Config, "jumpuser")
+	d.Config["jumphost"] = "bastion1"
+	if hops := parseJumpHosts(d); hops[0].User != "sweet" {
+		t.Errorf("Hop should default to the device user: %+v", hops[0])
+	}
+}
+
+func TestJumpHostCollection(t *testing.T) {
+	device := &fakeShell{
+		Prompt: "router1#",
+		Responses: map[string]string{
+			"show running-config": "Building configuration...\r\n\r\nhostname router1\r\n",
+		},
+	}
+	deviceAddr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), device)
+	bastionAddr := startTestSSHServer(t, passwordServerConfig("jumper", "jumppw"), &fakeShell{})
+
+	d := testSSHDevice(deviceAddr)
+	d.Config["jumphost"] = bastionAddr
+	d.Config["jumpuser"] = "jumper"
+	d.Config["jumppass"] = "jumppw"
+
+	result, err := newCiscoCollector().Collect(d)
+	if err != nil {
+		t.Fatalf("Collection through jump host failed: %s", err.Error())
+	}
+	if !strings.HasPrefix(result["config"], "hostname router1") {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+}
+
+func TestJumpHostFailureNamesHop(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %s", err.Error())
+	}
+	deadAddr := l.Addr().String()
+	l.Close()
+
+	d := testSSHDevice("127.0.0.1:22")
+	d.Config["jumphost"] = deadAddr
+	_, err = newCiscoCollector().Collect(d)
+	if err == nil {
+		t.Fatalf("Expected jump host failure")
+	}
+	host, _, _ := net.SplitHostPort(deadAddr)
+	if !strings.Contains(err.Error(), "jump host "+host+" (hop 1 of 1) failed") {
+		t.Errorf("Error doesn't name the hop: %s", err.Error())
+	}
+}
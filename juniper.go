@@ -2,8 +2,24 @@ package sweet
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 )
 
+// the end of an operational mode prompt like "user@router> "
+var junosPromptEnd = regexp.MustCompile(`>\s*$`)
+
+// chassis cluster members show their role above the prompt, e.g. "{primary:node0}"
+var junosClusterStatus = regexp.MustCompile(`\{[a-z-]+:node[0-9]+\}`)
+
+// the junos-formats choices, each saved as its own result
+var junosFormats = map[string]struct{ name, pipe string }{
+	"text": {"config", ""},
+	"set":  {"config-set", " | display set"},
+	"xml":  {"config-xml", " | display xml"},
+	"json": {"config-json", " | display json"},
+}
+
 type JunOS struct {
 }
 
@@ -14,33 +30,73 @@ func newJunOSCollector() Collector {
 func (collector JunOS) Collect(device DeviceConfig) (map[string]string, error) {
 	result := make(map[string]string)
 
+	formats := splitList(device.Config["junos-formats"])
+	if len(formats) == 0 {
+		formats = []string{"text"}
+	}
+	for _, format := range formats {
+		if _, ok := junosFormats[format]; !ok {
+			return result, fmt.Errorf("Unknown junos-formats entry %s - use text, set, xml or json", format)
+		}
+	}
+
 	c, err := newSSHCollector(device)
 	if err != nil {
-		return result, fmt.Errorf("Error connecting to %s: %s", device.Hostname, err.Error())
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
 	}
+	defer c.Close()
 
-	if err := expect("assword:", c.Receive); err != nil {
-		return result, fmt.Errorf("Missing password prompt: %s", err.Error())
+	if err := c.login(device); err != nil {
+		return result, err
 	}
-	c.Send <- device.Config["pass"] + "\n"
-	multi := []string{">", "assword:"}
-	m, err := expectMulti(multi, c.Receive)
+	m, _, err := expectMultiRegexp([]*regexp.Regexp{junosPromptEnd, passwordPromptEnd}, c.Receive)
 	if err != nil {
 		return result, fmt.Errorf("Invalid response to password: %s", err.Error())
 	}
-	if m == "assword:" {
+	if m == 1 {
 		return result, fmt.Errorf("Bad username or password.")
 	}
 	c.Send <- "set cli screen-length 0\n"
-	if err := expect(">", c.Receive); err != nil {
+	_, output, err := expectMultiRegexp([]*regexp.Regexp{junosPromptEnd}, c.Receive)
+	if err != nil {
 		return result, fmt.Errorf("Command 'set cli screen-length 0' failed: %s", err.Error())
 	}
-	c.Send <- "show configuration\n"
-	result["config"], err = expectSaveTimeout("#\n", c.Receive, device.CommandTimeout)
+	if junosClusterStatus.MatchString(output) && !junosHasFormat(formats, "set") {
+		// SRX clusters are usually managed with set commands, so keep that form too
+		formats = append(formats, "set")
+	}
+	prompt, err := c.learnPrompt(junosPromptEnd)
 	if err != nil {
-		return result, fmt.Errorf("Command 'show configuration' failed: %s", err.Error())
+		return result, err
+	}
+	for _, format := range formats {
+		command := "show configuration" + junosFormats[format].pipe
+		c.Send <- command + "\n"
+		output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+		if err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", command, err.Error())
+		}
+		result[junosFormats[format].name] = junosCleanOutput(output, command)
 	}
 	c.Send <- "exit\n"
 
 	return result, nil
 }
+
+func junosHasFormat(formats []string, format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// junosCleanOutput drops the echoed command and the prompt, including a cluster member's status line
+func junosCleanOutput(output, command string) string {
+	output = cleanCommandOutput(output, command)
+	if i := strings.LastIndex(output, "\n"); i >= 0 && junosClusterStatus.MatchString(output[i+1:]) {
+		output = output[:i]
+	}
+	return strings.Trim(output, "\r\n")
+}
@@ -32,7 +32,11 @@ func TestJunOSGood(t *testing.T) {
 
 	d.Target = d.Hostname
 
-	s := CollectJunOS(*d)
+	s, err := newJunOSCollector().Collect(*d)
+	if err != nil {
+		t.Errorf("Collection failed: %s", err.Error())
+		return
+	}
 	if !strings.Contains(s["config"], "version ") {
 		t.Errorf("Config missing version line")
 	}
@@ -41,3 +45,105 @@ func TestJunOSGood(t *testing.T) {
 	}
 
 }
+
+func TestJunOSCluster(t *testing.T) {
+	shell := &fakeShell{
+		Banner: "--- JUNOS 12.1X46-D40.2 built 2015-09-22\r\n\r\n{primary:node0}\r\n",
+		Prompt: "sweet@srx1> ",
+		Responses: map[string]string{
+			"set cli screen-length 0":          "Screen length set to 0\r\n\r\n{primary:node0}\r\n",
+			"show configuration":               "## Last commit: 2026-10-18 10:00:00 UTC by admin\r\nversion 12.1X46-D40.2;\r\n\r\n{primary:node0}\r\n",
+			"show configuration | display set": "set version 12.1X46-D40.2\r\nset chassis cluster reth-count 2\r\n\r\n{primary:node0}\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newJunOSCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("JunOS collection failed: %s", err.Error())
+	}
+	if !strings.Contains(result["config"], "version 12.1X46-D40.2;") {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+	if result["config-set"] != "set version 12.1X46-D40.2\nset chassis cluster reth-count 2" {
+		t.Errorf("Bad config-set result: %q", result["config-set"])
+	}
+}
+
+func TestJunOSStandalone(t *testing.T) {
+	shell := &fakeShell{
+		Prompt:    "sweet@mx1> ",
+		Responses: map[string]string{"show configuration": "version 15.1R7;\r\n"},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newJunOSCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("JunOS collection failed: %s", err.Error())
+	}
+	if _, ok := result["config-set"]; ok {
+		t.Errorf("Standalone device should not have a config-set result")
+	}
+}
+
+func TestJunOSFormats(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "sweet@mx1> ",
+		Responses: map[string]string{
+			"show configuration":                "version 15.1R7;\r\n",
+			"show configuration | display set":  "set version 15.1R7\r\n",
+			"show configuration | display xml":  "<rpc-reply>\r\n<configuration/>\r\n</rpc-reply>\r\n",
+			"show configuration | display json": "{\r\n    \"configuration\" : {}\r\n}\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Config["junos-formats"] = "text, set,xml,json"
+
+	result, err := newJunOSCollector().Collect(d)
+	if err != nil {
+		t.Fatalf("JunOS collection failed: %s", err.Error())
+	}
+	expected := map[string]string{
+		"config-set":  "set version 15.1R7",
+		"config-xml":  "<rpc-reply>\n<configuration/>\n</rpc-reply>",
+		"config-json": "{\n    \"configuration\" : {}\n}",
+	}
+	for name, want := range expected {
+		if result[name] != want {
+			t.Errorf("Bad %s result: %q", name, result[name])
+		}
+	}
+	if !strings.Contains(result["config"], "version 15.1R7;") {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+}
+
+func TestJunOSBadFormat(t *testing.T) {
+	d := testSSHDevice("127.0.0.1:1")
+	d.Config["junos-formats"] = "text,yaml"
+	if _, err := newJunOSCollector().Collect(d); err == nil {
+		t.Errorf("Expected error for unknown junos-formats entry")
+	}
+}
+
+func TestJunOSClusterAfterPromptLikeOutput(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "sweet@srx1> ",
+		Responses: map[string]string{
+			"set cli screen-length 0": strings.Repeat("warning: configuration database modified => commit pending\r\n", 6) +
+				"Screen length set to 0\r\n\r\n{primary:node0}\r\n",
+			"show configuration":               "version 12.1X46-D40.2;\r\n\r\n{primary:node0}\r\n",
+			"show configuration | display set": "set version 12.1X46-D40.2\r\n\r\n{primary:node0}\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newJunOSCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("JunOS collection failed: %s", err.Error())
+	}
+	if result["config-set"] != "set version 12.1X46-D40.2" {
+		t.Errorf("Cluster member missed after a \">\" in the output: %q", result["config-set"])
+	}
+}
@@ -0,0 +1,140 @@
+package sweet
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// knownHostsFile is Sweet's own known_hosts, kept in the workspace.
+const knownHostsFile = "known_hosts"
+
+// serialize trust-on-first-use writes from concurrent collectors
+var knownHostsLock sync.Mutex
+
+// HostKeyChangedError is returned when a device presents a different key than the one on record.
+type HostKeyChangedError struct {
+	Hostname    string
+	Fingerprint string
+	Source      string
+}
+
+func (e *HostKeyChangedError) Error() string {
+	return fmt.Sprintf("HOST KEY CHANGED for %s: got %s which does not match %s", e.Hostname, e.Fingerprint, e.Source)
+}
+
+//// Pick the host key check for a device, and the key algorithms to ask for
+func hostKeyCheck(device DeviceConfig, addr string) (ssh.HostKeyCallback, []string, error) {
+	if device.Config["insecure"] == "true" {
+		return ssh.InsecureIgnoreHostKey(), nil, nil
+	}
+	if len(device.Config["hostkey"]) > 0 {
+		return pinnedHostKey(device.Hostname, device.Config["hostkey"])
+	}
+	path := knownHostsFile
+	if len(device.Config["known-hosts"]) > 0 {
+		path = device.Config["known-hosts"]
+	}
+	return trustOnFirstUse(device.Hostname, path), knownHostKeyAlgorithms(path, addr), nil
+}
+
+// pinnedHostKey accepts only the configured key, given either as a
+// "SHA256:..." fingerprint or in authorized_keys format.
+func pinnedHostKey(hostname, pin string) (ssh.HostKeyCallback, []string, error) {
+	pin = strings.TrimSpace(pin)
+	var want ssh.PublicKey
+	var algorithms []string
+	if !strings.HasPrefix(pin, "SHA256:") {
+		var err error
+		want, _, _, _, err = ssh.ParseAuthorizedKey([]byte(pin))
+		if err != nil {
+			return nil, nil, fmt.Errorf("Bad hostkey setting for %s: %s", hostname, err.Error())
+		}
+		algorithms = keyTypeAlgorithms(want.Type())
+	}
+	return func(addr string, remote net.Addr, key ssh.PublicKey) error {
+		if want == nil && ssh.FingerprintSHA256(key) == pin {
+			return nil
+		}
+		if want != nil && bytes.Equal(want.Marshal(), key.Marshal()) {
+			return nil
+		}
+		return &HostKeyChangedError{Hostname: hostname, Fingerprint: ssh.FingerprintSHA256(key), Source: "the pinned hostkey"}
+	}, algorithms, nil
+}
+
+// trustOnFirstUse records unknown keys in the known_hosts file and rejects changed ones.
+func trustOnFirstUse(hostname, path string) ssh.HostKeyCallback {
+	return func(addr string, remote net.Addr, key ssh.PublicKey) error {
+		knownHostsLock.Lock()
+		defer knownHostsLock.Unlock()
+
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("Unable to open %s: %s", path, err.Error())
+		}
+		defer f.Close()
+
+		check, err := knownhosts.New(path)
+		if err != nil {
+			return fmt.Errorf("Unable to read %s: %s", path, err.Error())
+		}
+		err = check(addr, remote, key)
+		var keyErr *knownhosts.KeyError
+		if err == nil {
+			return nil
+		} else if !errors.As(err, &keyErr) {
+			return err
+		} else if len(keyErr.Want) > 0 {
+			return &HostKeyChangedError{Hostname: hostname, Fingerprint: ssh.FingerprintSHA256(key), Source: fmt.Sprintf("%s line %d", path, keyErr.Want[0].Line)}
+		}
+
+		// first contact: trust and remember this key
+		line := knownhosts.Line([]string{knownhosts.Normalize(addr)}, key) + "\n"
+		if _, err := f.WriteString(line); err != nil {
+			return fmt.Errorf("Unable to update %s: %s", path, err.Error())
+		}
+		return nil
+	}
+}
+
+// knownHostKeyAlgorithms lists the algorithms for keys already on record for
+// addr, so a device that also has other key types isn't mistaken for a changed one.
+func knownHostKeyAlgorithms(path, addr string) []string {
+	check, err := knownhosts.New(path)
+	if err != nil {
+		return nil
+	}
+	_, probe, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil
+	}
+	signer, err := ssh.NewSignerFromKey(probe)
+	if err != nil {
+		return nil
+	}
+	var keyErr *knownhosts.KeyError
+	if !errors.As(check(addr, &net.TCPAddr{IP: net.IPv4zero}, signer.PublicKey()), &keyErr) {
+		return nil
+	}
+	var algorithms []string
+	for _, known := range keyErr.Want {
+		algorithms = append(algorithms, keyTypeAlgorithms(known.Key.Type())...)
+	}
+	return algorithms
+}
+
+func keyTypeAlgorithms(keyType string) []string {
+	if keyType == ssh.KeyAlgoRSA {
+		return []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	}
+	return []string{keyType}
+}
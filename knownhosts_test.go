@@ -0,0 +1,98 @@
+package sweet
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"golang.org/x/crypto/ssh"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testHostKey(t *testing.T) ssh.PublicKey {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %s", err.Error())
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatalf("Unable to create signer: %s", err.Error())
+	}
+	return signer.PublicKey()
+}
+
+var testRemote = &net.TCPAddr{IP: net.ParseIP("10.1.1.1"), Port: 22}
+
+func TestKnownHostsTrustOnFirstUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), knownHostsFile)
+	key := testHostKey(t)
+	check := trustOnFirstUse("router1", path)
+
+	if err := check("router1:22", testRemote, key); err != nil {
+		t.Fatalf("First use should be trusted: %s", err.Error())
+	}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("known_hosts not written: %s", err.Error())
+	}
+	if !strings.HasPrefix(string(raw), "router1 ssh-ed25519 ") {
+		t.Errorf("Bad known_hosts line: %s", raw)
+	}
+	if err := check("router1:22", testRemote, key); err != nil {
+		t.Errorf("Known key should be accepted: %s", err.Error())
+	}
+
+	err = check("router1:22", testRemote, testHostKey(t))
+	var changed *HostKeyChangedError
+	if !errors.As(err, &changed) {
+		t.Fatalf("Expected HostKeyChangedError but got: %v", err)
+	}
+	if changed.Hostname != "router1" || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Bad host key error: %s", err.Error())
+	}
+
+	algorithms := knownHostKeyAlgorithms(path, "router1:22")
+	if len(algorithms) != 1 || algorithms[0] != ssh.KeyAlgoED25519 {
+		t.Errorf("Bad known algorithms: %v", algorithms)
+	}
+	if algorithms := knownHostKeyAlgorithms(path, "router2:22"); algorithms != nil {
+		t.Errorf("Unknown host should have no algorithms: %v", algorithms)
+	}
+}
+
+func TestKnownHostsPinned(t *testing.T) {
+	key := testHostKey(t)
+	for _, pin := range []string{ssh.FingerprintSHA256(key), string(ssh.MarshalAuthorizedKey(key))} {
+		check, _, err := pinnedHostKey("router1", pin)
+		if err != nil {
+			t.Fatalf("Bad pin %s: %s", pin, err.Error())
+		}
+		if err := check("router1:22", testRemote, key); err != nil {
+			t.Errorf("Pinned key should be accepted: %s", err.Error())
+		}
+		var changed *HostKeyChangedError
+		if !errors.As(check("router1:22", testRemote, testHostKey(t)), &changed) {
+			t.Errorf("Other key should be rejected for pin %s", pin)
+		}
+	}
+	if _, _, err := pinnedHostKey("router1", "not a key"); err == nil {
+		t.Errorf("Expected error for bad pin")
+	}
+}
+
+func TestKnownHostsChangedCollection(t *testing.T) {
+	shell := &fakeShell{Prompt: "router1#"}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	delete(d.Config, "insecure")
+	d.Config["hostkey"] = ssh.FingerprintSHA256(testHostKey(t))
+
+	_, err := newCiscoCollector().Collect(d)
+	var changed *HostKeyChangedError
+	if !errors.As(err, &changed) {
+		t.Errorf("Expected HostKeyChangedError from collector but got: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package sweet
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kballard/go-shellquote"
+	"golang.org/x/crypto/ssh"
+	"strings"
+	"time"
+)
+
+// Linux collects files and command output from a Unix server, running each
+// over its own SSH exec channel rather than through an interactive shell.
+type Linux struct {
+}
+
+func newLinuxCollector() Collector {
+	return Linux{}
+}
+
+func (collector Linux) Collect(device DeviceConfig) (map[string]string, error) {
+	result := make(map[string]string)
+
+	files := splitList(device.Config["files"])
+	commands := splitList(device.Config["commands"])
+	if len(files) == 0 && len(commands) == 0 {
+		return result, fmt.Errorf("No files or commands configured for %s.", device.Hostname)
+	}
+
+	if device.Config["transport"] == "telnet" {
+		return result, fmt.Errorf("The linux method needs SSH, telnet is not supported.")
+	}
+
+	client, closers, err := sshConnect(device)
+	if err != nil {
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
+	}
+	closeAll := func() {
+		for _, closer := range closers {
+			closer.Close()
+		}
+	}
+	defer closeAll()
+	if device.Timeout > 0 {
+		time.AfterFunc(device.Timeout, closeAll)
+	}
+
+	for _, file := range files {
+		output, err := linuxRun(client, shellquote.Join("cat", "--", file))
+		if err != nil {
+			return result, fmt.Errorf("Unable to read %s: %s", file, err.Error())
+		}
+		result["file-"+cleanName(strings.TrimPrefix(file, "/"))] = output
+	}
+	for _, command := range commands {
+		output, err := linuxRun(client, command)
+		if err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", command, err.Error())
+		}
+		result["command-"+cleanName(command)] = output
+	}
+
+	return result, nil
+}
+
+// linuxRun runs one command and returns its standard output
+func linuxRun(client *ssh.Client, command string) (string, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	if err := session.Run(command); err != nil {
+		return "", fmt.Errorf("%s - %s", err.Error(), strings.TrimRight(stderr.String(), "\n"))
+	}
+	return stdout.String(), nil
+}
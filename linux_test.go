@@ -0,0 +1,56 @@
+package sweet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLinuxCollect(t *testing.T) {
+	shell := &fakeShell{
+		Responses: map[string]string{
+			"cat -- /etc/hosts":                 "127.0.0.1 localhost\n",
+			"cat -- '/etc/nginx/sites enabled'": "server {\n}\n",
+			"iptables-save":                     "*filter\n:INPUT ACCEPT [0:0]\nCOMMIT\n",
+			"ip route":                          "default via 192.0.2.1 dev eth0\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Config["files"] = "/etc/hosts,/etc/nginx/sites enabled"
+	d.Config["commands"] = "iptables-save, ip route"
+
+	result, err := newLinuxCollector().Collect(d)
+	if err != nil {
+		t.Fatalf("Linux collection failed: %s", err.Error())
+	}
+	expected := map[string]string{
+		"file-etc-hosts":               "127.0.0.1 localhost\n",
+		"file-etc-nginx-sites-enabled": "server {\n}\n",
+		"command-iptables-save":        "*filter\n:INPUT ACCEPT [0:0]\nCOMMIT\n",
+		"command-ip-route":             "default via 192.0.2.1 dev eth0\n",
+	}
+	if len(result) != len(expected) {
+		t.Errorf("Expected %d results, got %d: %v", len(expected), len(result), result)
+	}
+	for name, want := range expected {
+		if result[name] != want {
+			t.Errorf("Bad %s result: %q", name, result[name])
+		}
+	}
+}
+
+func TestLinuxErrors(t *testing.T) {
+	shell := &fakeShell{}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Config["commands"] = "missing-tool"
+
+	_, err := newLinuxCollector().Collect(d)
+	if err == nil || !strings.Contains(err.Error(), "missing-tool: command not found") {
+		t.Errorf("Bad error for failed command: %v", err)
+	}
+
+	if _, err := newLinuxCollector().Collect(testSSHDevice(addr)); err == nil {
+		t.Errorf("Expected error with nothing to collect")
+	}
+}
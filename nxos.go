@@ -0,0 +1,137 @@
+package sweet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type NXOS struct {
+}
+
+func newNXOSCollector() Collector {
+	return NXOS{}
+}
+
+func (collector NXOS) Collect(device DeviceConfig) (map[string]string, error) {
+	result := make(map[string]string)
+
+	c, err := newSSHCollector(device)
+	if err != nil {
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
+	}
+	defer c.Close()
+
+	if err := c.login(device); err != nil {
+		return result, err
+	}
+	m, _, err := expectMultiRegexp([]*regexp.Regexp{ciscoPromptEnd, passwordPromptEnd}, c.Receive)
+	if err != nil {
+		return result, fmt.Errorf("Invalid response to password: %s", err.Error())
+	}
+	if m == 1 {
+		return result, fmt.Errorf("Bad username or password.")
+	}
+	prompt, err := c.learnPrompt(ciscoPromptEnd)
+	if err != nil {
+		return result, err
+	}
+	if err := nxosCollectContext(c, device, prompt, "", result); err != nil {
+		return result, err
+	}
+
+	if len(device.Config["nxos-vdcs"]) > 0 && device.Config["nxos-vdcs"] != "false" {
+		vdcs, err := nxosVDCs(c, device, prompt)
+		if err != nil {
+			return result, err
+		}
+		for _, vdc := range vdcs {
+			// the VDC has its own prompt, e.g. "n7k-prod#"
+			c.Send <- "switchto vdc " + vdc + "\n"
+			_, output, err := expectMultiRegexp([]*regexp.Regexp{ciscoPromptEnd}, c.Receive)
+			if err != nil {
+				return result, fmt.Errorf("Command 'switchto vdc %s' failed: %s", vdc, err.Error())
+			}
+			if err := nxosCollectContext(c, device, promptRegexp(lastLine(output)), "vdc-"+vdc+"-", result); err != nil {
+				return result, err
+			}
+			c.Send <- "switchback\n"
+			if err := expectRegexp(prompt, c.Receive); err != nil {
+				return result, fmt.Errorf("Command 'switchback' from vdc %s failed: %s", vdc, err.Error())
+			}
+		}
+	}
+
+	c.Send <- "exit\n"
+
+	return result, nil
+}
+
+// nxosCollectContext saves the current VDC's results, with names starting with prefix
+func nxosCollectContext(c *SSHCollector, device DeviceConfig, prompt *regexp.Regexp, prefix string, result map[string]string) error {
+	c.Send <- "terminal length 0\n"
+	if err := expectRegexp(prompt, c.Receive); err != nil {
+		return fmt.Errorf("Command 'terminal length 0' failed: %s", err.Error())
+	}
+	commands := []struct{ name, command string }{
+		{"config", "show running-config"},
+		{"startup-config", "show startup-config"},
+		{"version", "show version"},
+		{"module", "show module"},
+	}
+	if device.Config["nxos-checkpoints"] == "true" {
+		// new, removed and replaced rollback checkpoints show up as changes
+		commands = append(commands, struct{ name, command string }{"checkpoints", "show checkpoint summary"})
+	}
+	for _, cmd := range commands {
+		c.Send <- cmd.command + "\n"
+		output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+		if err != nil {
+			return fmt.Errorf("Command '%s' failed: %s", cmd.command, err.Error())
+		}
+		result[prefix+cmd.name] = nxosCleanConfig(cleanCommandOutput(output, cmd.command))
+	}
+	return nil
+}
+
+// nxosVDCs lists the VDCs to visit: either from the nxos-vdcs option, or
+// every non-default VDC in "show vdc" when it is just "true".
+func nxosVDCs(c *SSHCollector, device DeviceConfig, prompt *regexp.Regexp) ([]string, error) {
+	if device.Config["nxos-vdcs"] != "true" {
+		return splitList(device.Config["nxos-vdcs"]), nil
+	}
+	c.Send <- "show vdc\n"
+	output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("Command 'show vdc' failed: %s", err.Error())
+	}
+	return parseNXOSVDCs(cleanCommandOutput(output, "show vdc")), nil
+}
+
+// parseNXOSVDCs reads VDC names from "show vdc", skipping the default VDC (id 1)
+func parseNXOSVDCs(output string) []string {
+	vdcs := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] == "1" {
+			continue
+		}
+		if strings.Trim(fields[0], "0123456789") != "" {
+			continue // headers and separators
+		}
+		vdcs = append(vdcs, fields[1])
+	}
+	return vdcs
+}
+
+// nxosCleanConfig removes the timestamp headers NX-OS prints on every run
+func nxosCleanConfig(config string) string {
+	lines := []string{}
+	for _, line := range strings.Split(config, "\n") {
+		if strings.HasPrefix(line, "!Time:") || strings.HasPrefix(line, "!Running configuration last done at") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,84 @@
+package sweet
+
+import (
+	"testing"
+)
+
+const nxosTestConfig = "\r\n!Command: show running-config\r\n!Running configuration last done at: Mon Oct 12 10:01:02 2026\r\n!Time: Tue Oct 13 11:22:33 2026\r\n\r\nversion 7.0(3)I7(8)\r\nhostname n7k\r\n"
+
+func TestNXOSCollect(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "n7k# ",
+		Responses: map[string]string{
+			"show running-config": nxosTestConfig,
+			"show startup-config": "!Command: show startup-config\r\n!Time: Tue Oct 13 11:22:34 2026\r\n!Startup config saved at: Mon Oct 12 10:01:05 2026\r\nversion 7.0(3)I7(8)\r\n",
+			"show version":        "Cisco Nexus Operating System (NX-OS) Software\r\n",
+			"show module":         "Mod  Ports  Module-Type\r\n1    48     Supervisor\r\n",
+			"show vdc": "vdc_id  vdc_name    state   mac                type      lc\r\n" +
+				"------  --------    -----   ----------         --------- ------\r\n" +
+				"1       n7k         active  00:26:51:c7:a3:41  Admin     None\r\n" +
+				"2       prod        active  00:26:51:c7:a3:42  Ethernet  m1 f1\r\n",
+		},
+	}
+	shell.Hooks = map[string]func(){
+		"switchto vdc prod": func() { shell.Prompt = "n7k-prod# " },
+		"switchback":        func() { shell.Prompt = "n7k# " },
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Config["nxos-vdcs"] = "true"
+
+	result, err := newNXOSCollector().Collect(d)
+	if err != nil {
+		t.Fatalf("NX-OS collection failed: %s", err.Error())
+	}
+	want := "!Command: show running-config\n\nversion 7.0(3)I7(8)\nhostname n7k"
+	if result["config"] != want {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+	if result["startup-config"] != "!Command: show startup-config\n!Startup config saved at: Mon Oct 12 10:01:05 2026\nversion 7.0(3)I7(8)" {
+		t.Errorf("Bad startup-config result: %q", result["startup-config"])
+	}
+	for _, name := range []string{"version", "module", "vdc-prod-config", "vdc-prod-startup-config", "vdc-prod-version", "vdc-prod-module"} {
+		if len(result[name]) == 0 {
+			t.Errorf("Missing %s result", name)
+		}
+	}
+	if len(result) != 8 {
+		t.Errorf("Expected 8 results but got %d", len(result))
+	}
+}
+
+func TestNXOSCheckpoints(t *testing.T) {
+	checkpoints := "User Checkpoint Summary\r\n" +
+		"--------------------------------------------------------------------------------\r\n" +
+		"1) before-upgrade:\r\nCreated by admin\r\nCreated at Mon, 10:01:02 18 Oct 2026\r\nSize is 28,629 bytes\r\nDescription: None\r\n"
+	shell := &fakeShell{
+		Prompt: "n7k# ",
+		Responses: map[string]string{
+			"show running-config":     nxosTestConfig,
+			"show checkpoint summary": checkpoints,
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Config["nxos-checkpoints"] = "true"
+
+	result, err := newNXOSCollector().Collect(d)
+	if err != nil {
+		t.Fatalf("NX-OS collection failed: %s", err.Error())
+	}
+	want := "User Checkpoint Summary\n" +
+		"--------------------------------------------------------------------------------\n" +
+		"1) before-upgrade:\nCreated by admin\nCreated at Mon, 10:01:02 18 Oct 2026\nSize is 28,629 bytes\nDescription: None"
+	if result["checkpoints"] != want {
+		t.Errorf("Bad checkpoints result: %q", result["checkpoints"])
+	}
+}
+
+func TestNXOSParseVDCs(t *testing.T) {
+	vdcs := parseNXOSVDCs("vdc_id  vdc_name  state\n------  --------  -----\n1  n7k  active\n2  prod  active\n3  lab  active\n")
+	if len(vdcs) != 2 || vdcs[0] != "prod" || vdcs[1] != "lab" {
+		t.Errorf("Bad VDCs: %v", vdcs)
+	}
+}
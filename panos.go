@@ -0,0 +1,213 @@
+package sweet
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PANOS collects from a Palo Alto firewall's XML API instead of its CLI.
+type PANOS struct {
+}
+
+// panosResponse is the envelope every XML API call returns.
+type panosResponse struct {
+	Status string `xml:"status,attr"`
+	Code   string `xml:"code,attr"`
+	Result struct {
+		Inner string `xml:",innerxml"`
+	} `xml:"result"`
+	Msg struct {
+		Inner string `xml:",innerxml"`
+	} `xml:"msg"`
+}
+
+// panosConn closes the jump host tunnels along with the connection they carry.
+type panosConn struct {
+	net.Conn
+	closers []io.Closer
+}
+
+func (c panosConn) Close() error {
+	err := c.Conn.Close()
+	for _, closer := range c.closers {
+		closer.Close()
+	}
+	return err
+}
+
+func newPANOSCollector() Collector {
+	return PANOS{}
+}
+
+func (collector PANOS) Collect(device DeviceConfig) (map[string]string, error) {
+	result := make(map[string]string)
+
+	client, err := panosClient(device)
+	if err != nil {
+		return result, err
+	}
+	defer client.CloseIdleConnections()
+	endpoint := "https://" + device.Target
+	if len(device.Config["port"]) > 0 {
+		endpoint = "https://" + net.JoinHostPort(device.Target, device.Config["port"])
+	}
+	endpoint += "/api/"
+
+	key := device.Config["api-key"]
+	if len(key) == 0 {
+		key, err = panosRequest(client, endpoint, url.Values{
+			"type":     {"keygen"},
+			"user":     {device.Config["user"]},
+			"password": {device.Config["pass"]},
+		})
+		if err != nil {
+			return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
+		}
+		key = panosCharData(key)
+	}
+
+	commands := []struct{ name, command string }{
+		{"config", "<show><config><running></running></config></show>"},
+		{"candidate-diff", "<show><config><diff></diff></config></show>"},
+		{"version", "<show><system><info></info></system></show>"},
+	}
+	for _, cmd := range commands {
+		output, err := panosRequest(client, endpoint, url.Values{"type": {"op"}, "key": {key}, "cmd": {cmd.command}})
+		if err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %w", cmd.command, err)
+		}
+		result[cmd.name] = panosText(output)
+	}
+
+	return result, nil
+}
+
+// panosClient talks HTTPS to the device, through its jump hosts if it has any
+func panosClient(device DeviceConfig) (*http.Client, error) {
+	tlsConfig, err := panosTLSConfig(device)
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{
+		TLSClientConfig: tlsConfig,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, closers, err := dialDevice(device, addr)
+			if err != nil {
+				return nil, err
+			}
+			return panosConn{conn, closers}, nil
+		},
+	}
+	return &http.Client{Transport: transport, Timeout: device.Timeout}, nil
+}
+
+// panosTLSConfig picks the certificate check for a device: none with
+// insecure, the pinned tls-fingerprint, the CAs in ca-file, or the system CAs.
+func panosTLSConfig(device DeviceConfig) (*tls.Config, error) {
+	if device.Config["insecure"] == "true" {
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+	if pin := device.Config["tls-fingerprint"]; len(pin) > 0 {
+		want, err := hex.DecodeString(strings.Replace(strings.TrimPrefix(strings.TrimSpace(pin), "SHA256:"), ":", "", -1))
+		if err != nil || len(want) != sha256.Size {
+			return nil, fmt.Errorf("Bad tls-fingerprint setting for %s: expected a SHA256 fingerprint like AB:CD:...", device.Hostname)
+		}
+		// a pinned self-signed certificate needs no CA, only the exact certificate
+		return &tls.Config{InsecureSkipVerify: true, VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) > 0 {
+				if got := sha256.Sum256(rawCerts[0]); bytes.Equal(got[:], want) {
+					return nil
+				}
+				return &HostKeyChangedError{Hostname: device.Hostname, Fingerprint: tlsFingerprint(rawCerts[0]), Source: "the pinned tls-fingerprint"}
+			}
+			return fmt.Errorf("No certificate from %s", device.Hostname)
+		}}, nil
+	}
+	if len(device.Config["ca-file"]) > 0 {
+		pem, err := ioutil.ReadFile(device.Config["ca-file"])
+		if err != nil {
+			return nil, fmt.Errorf("Bad ca-file setting for %s: %s", device.Hostname, err.Error())
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("Bad ca-file setting for %s: no PEM certificates in %s", device.Hostname, device.Config["ca-file"])
+		}
+		return &tls.Config{RootCAs: pool}, nil
+	}
+	return &tls.Config{}, nil
+}
+
+// tlsFingerprint formats a certificate's SHA256 fingerprint like openssl does
+func tlsFingerprint(cert []byte) string {
+	sum := sha256.Sum256(cert)
+	pairs := make([]string, len(sum))
+	for i, b := range sum {
+		pairs[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(pairs, ":")
+}
+
+// panosRequest POSTs an API call, so keys and passwords stay out of URLs and
+// logs, and returns the raw contents of the response's <result>.
+func panosRequest(client *http.Client, endpoint string, values url.Values) (string, error) {
+	resp, err := client.PostForm(endpoint, values)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var r panosResponse
+	if err := xml.Unmarshal(body, &r); err != nil {
+		return "", fmt.Errorf("Bad XML API response (HTTP %d): %s", resp.StatusCode, err.Error())
+	}
+	if r.Status != "success" {
+		msg := panosCharData(r.Msg.Inner + r.Result.Inner)
+		if len(msg) == 0 {
+			msg = "no message"
+		}
+		return "", fmt.Errorf("XML API error (code %s): %s", r.Code, msg)
+	}
+	return r.Result.Inner, nil
+}
+
+// panosText returns a result as trimmed XML, or as plain text when it holds
+// no elements (e.g. a config diff) so entities and CDATA are decoded.
+func panosText(inner string) string {
+	trimmed := strings.TrimSpace(inner)
+	if strings.HasPrefix(trimmed, "<") && !strings.HasPrefix(trimmed, "<![CDATA[") {
+		return trimmed
+	}
+	return panosCharData(inner)
+}
+
+// panosCharData joins all the text in a piece of XML, dropping the markup
+func panosCharData(inner string) string {
+	text := ""
+	d := xml.NewDecoder(bytes.NewBufferString(inner))
+	for {
+		token, err := d.Token()
+		if err != nil {
+			break
+		}
+		if t, ok := token.(xml.CharData); ok {
+			text += string(t)
+		}
+	}
+	return strings.TrimSpace(text)
+}
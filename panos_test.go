@@ -0,0 +1,147 @@
+package sweet
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// panosTestServer is a stand-in for the firewall's XML API.
+func panosTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/" || r.Method != "POST" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.FormValue("type") == "keygen" {
+			if r.FormValue("user") != "sweet" || r.FormValue("password") != "sweetpw" {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `<response status="error" code="403"><result><msg>Invalid Credential</msg></result></response>`)
+				return
+			}
+			fmt.Fprint(w, `<response status="success"><result><key>TESTKEY==</key></result></response>`)
+			return
+		}
+		if r.FormValue("key") != "TESTKEY==" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<response status="error" code="403"><result><msg>Invalid credentials.</msg></result></response>`)
+			return
+		}
+		switch r.FormValue("cmd") {
+		case "<show><config><running></running></config></show>":
+			fmt.Fprint(w, "<response status=\"success\"><result>\n  <config version=\"10.1.0\"><devices/></config>\n</result></response>")
+		case "<show><config><diff></diff></config></show>":
+			fmt.Fprint(w, `<response status="success"><result><![CDATA[+ set address web ip-netmask 192.0.2.10 & more]]></result></response>`)
+		case "<show><system><info></info></system></show>":
+			fmt.Fprint(w, `<response status="success"><result><system><hostname>fw1</hostname><sw-version>10.1.0</sw-version></system></result></response>`)
+		default:
+			fmt.Fprint(w, `<response status="error" code="17"><msg><line>Unknown command</line></msg></response>`)
+		}
+	}))
+}
+
+func testPANOSDevice(server *httptest.Server) DeviceConfig {
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	d := DeviceConfig{Hostname: "fw1", Target: host, Timeout: 5 * time.Second, CommandTimeout: 200 * time.Millisecond}
+	d.Config = map[string]string{"user": "sweet", "pass": "sweetpw", "insecure": "true", "port": port}
+	return d
+}
+
+func TestPANOSCollect(t *testing.T) {
+	server := panosTestServer(t)
+	defer server.Close()
+
+	result, err := newPANOSCollector().Collect(testPANOSDevice(server))
+	if err != nil {
+		t.Fatalf("PAN-OS collection failed: %s", err.Error())
+	}
+	expected := map[string]string{
+		"config":         "<config version=\"10.1.0\"><devices/></config>",
+		"candidate-diff": "+ set address web ip-netmask 192.0.2.10 & more",
+		"version":        "<system><hostname>fw1</hostname><sw-version>10.1.0</sw-version></system>",
+	}
+	for name, want := range expected {
+		if result[name] != want {
+			t.Errorf("Bad %s result: %q", name, result[name])
+		}
+	}
+}
+
+func TestPANOSAPIKey(t *testing.T) {
+	server := panosTestServer(t)
+	defer server.Close()
+	d := testPANOSDevice(server)
+	d.Config["pass"] = ""
+	d.Config["api-key"] = "TESTKEY=="
+
+	if _, err := newPANOSCollector().Collect(d); err != nil {
+		t.Fatalf("PAN-OS collection with stored key failed: %s", err.Error())
+	}
+}
+
+func TestPANOSErrors(t *testing.T) {
+	server := panosTestServer(t)
+	defer server.Close()
+
+	d := testPANOSDevice(server)
+	d.Config["pass"] = "wrong"
+	if _, err := newPANOSCollector().Collect(d); err == nil || err.Error() != "Error connecting to fw1: XML API error (code 403): Invalid Credential" {
+		t.Errorf("Bad keygen error: %v", err)
+	}
+
+	d = testPANOSDevice(server)
+	d.Config["insecure"] = "false"
+	if _, err := newPANOSCollector().Collect(d); err == nil {
+		t.Errorf("Expected certificate error for self-signed server")
+	}
+}
+
+func TestPANOSCertificateChecks(t *testing.T) {
+	server := panosTestServer(t)
+	defer server.Close()
+	fingerprint := tlsFingerprint(server.Certificate().Raw)
+
+	d := testPANOSDevice(server)
+	d.Config["insecure"] = "false"
+	d.Config["tls-fingerprint"] = strings.ToLower(fingerprint)
+	if _, err := newPANOSCollector().Collect(d); err != nil {
+		t.Errorf("Collection with pinned certificate failed: %s", err.Error())
+	}
+
+	d.Config["tls-fingerprint"] = strings.Repeat("00:", 31) + "00"
+	_, err := newPANOSCollector().Collect(d)
+	var changed *HostKeyChangedError
+	if !errors.As(err, &changed) || changed.Fingerprint != fingerprint {
+		t.Errorf("Expected a changed certificate error but got %v", err)
+	}
+	d.Config["api-key"] = "TESTKEY=="
+	if _, err := newPANOSCollector().Collect(d); !errors.As(err, &changed) {
+		t.Errorf("Expected a changed certificate error with a stored key but got %v", err)
+	}
+
+	d.Config["tls-fingerprint"] = "SHA256:xyz"
+	if _, err := newPANOSCollector().Collect(d); err == nil || !strings.Contains(err.Error(), "Bad tls-fingerprint setting") {
+		t.Errorf("Expected a bad tls-fingerprint error but got %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644)
+	d = testPANOSDevice(server)
+	d.Config["insecure"] = "false"
+	d.Config["ca-file"] = caFile
+	if _, err := newPANOSCollector().Collect(d); err != nil {
+		t.Errorf("Collection with ca-file failed: %s", err.Error())
+	}
+	d.Config["ca-file"] = filepath.Join(t.TempDir(), "missing.pem")
+	if _, err := newPANOSCollector().Collect(d); err == nil || !strings.Contains(err.Error(), "Bad ca-file setting") {
+		t.Errorf("Expected a bad ca-file error but got %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package sweet
+
+import (
+	"fmt"
+	"github.com/vaughan0/go-ini"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProfilePrefix marks config file sections that define a profile, and
+// device methods that use one.
+const ProfilePrefix = "profile:"
+
+// Profile is a collector defined in the config file instead of in Go.
+type Profile struct {
+	Name                 string
+	Prompt               *regexp.Regexp
+	UsernamePrompt       *regexp.Regexp
+	PasswordPrompt       *regexp.Regexp
+	EnablePrompt         *regexp.Regexp // nil when the login lands at the privileged prompt
+	EnableCommand        string
+	EnablePasswordPrompt *regexp.Regexp
+	Setup                []string
+	Commands             []ProfileCommand
+	Cleanup              []*regexp.Regexp
+	Exit                 string
+}
+
+// ProfileCommand is one command whose output is saved under Name.
+type ProfileCommand struct {
+	Name    string
+	Command string
+}
+
+type ProfileCollector struct {
+	Profile *Profile
+}
+
+func newProfileCollector(profile *Profile) Collector {
+	return ProfileCollector{Profile: profile}
+}
+
+//// Build a profile from its config section
+func LoadProfile(name string, section map[string]string) (*Profile, error) {
+	p := &Profile{Name: name, EnableCommand: "enable", Exit: "exit"}
+	regexps := []struct {
+		key      string
+		fallback string
+		optional bool // may be left empty
+		dest     **regexp.Regexp
+	}{
+		{"prompt", "", false, &p.Prompt},
+		{"username-prompt", "sername:|ogin:", false, &p.UsernamePrompt},
+		{"password-prompt", "assword:", false, &p.PasswordPrompt},
+		{"enable-prompt", "", true, &p.EnablePrompt},
+		{"enable-password-prompt", "assword:", false, &p.EnablePasswordPrompt},
+	}
+	for _, r := range regexps {
+		expr, ok := section[r.key]
+		if !ok {
+			expr = r.fallback
+		}
+		if len(expr) == 0 {
+			if ok && !r.optional {
+				return nil, fmt.Errorf("Empty %s for profile %s.", r.key, name)
+			}
+			continue
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("Bad %s for profile %s: %s", r.key, name, err.Error())
+		}
+		*r.dest = re
+	}
+	if p.Prompt == nil {
+		return nil, fmt.Errorf("Profile %s needs a prompt regex.", name)
+	}
+	if command, ok := section["enable-command"]; ok {
+		p.EnableCommand = command
+	}
+	if command, ok := section["exit"]; ok {
+		p.Exit = command
+	}
+
+	// setup, setup.N, command.NAME and cleanup.N keys, in name order so runs are repeatable
+	keys := make([]string, 0, len(section))
+	for key := range section {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return profileKeyLess(keys[i], keys[j]) })
+	for _, key := range keys {
+		if key == "setup" || strings.HasPrefix(key, "setup.") {
+			p.Setup = append(p.Setup, section[key])
+		} else if strings.HasPrefix(key, "command.") {
+			p.Commands = append(p.Commands, ProfileCommand{Name: strings.TrimPrefix(key, "command."), Command: section[key]})
+		} else if strings.HasPrefix(key, "cleanup.") {
+			re, err := regexp.Compile(section[key])
+			if err != nil {
+				return nil, fmt.Errorf("Bad %s for profile %s: %s", key, name, err.Error())
+			}
+			p.Cleanup = append(p.Cleanup, re)
+		}
+	}
+	if len(p.Commands) == 0 {
+		return nil, fmt.Errorf("Profile %s has no command.NAME entries.", name)
+	}
+	return p, nil
+}
+
+// profileKeyLess orders keys by name, but numbered keys by number so e.g.
+// setup.2 runs before setup.10
+func profileKeyLess(a, b string) bool {
+	ai, bi := strings.LastIndex(a, "."), strings.LastIndex(b, ".")
+	if ai >= 0 && bi >= 0 && a[:ai] == b[:bi] {
+		an, aerr := strconv.Atoi(a[ai+1:])
+		bn, berr := strconv.Atoi(b[bi+1:])
+		if aerr == nil && berr == nil {
+			return an < bn
+		}
+	}
+	return a < b
+}
+
+//// Load the profiles from every .conf file in a directory
+func LoadProfileDir(dir string) (map[string]*Profile, error) {
+	profiles := make(map[string]*Profile)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return profiles, fmt.Errorf("Unable to read profiles directory %s: %s", dir, err.Error())
+	}
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".conf" {
+			continue
+		}
+		file, err := ini.LoadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return profiles, fmt.Errorf("Unable to read profile file %s: %s", f.Name(), err.Error())
+		}
+		for sectionName, section := range file {
+			if !strings.HasPrefix(sectionName, ProfilePrefix) {
+				continue
+			}
+			name := strings.TrimPrefix(sectionName, ProfilePrefix)
+			if _, ok := profiles[name]; ok {
+				return profiles, fmt.Errorf("Profile %s is defined more than once in %s.", name, dir)
+			}
+			profiles[name], err = LoadProfile(name, section)
+			if err != nil {
+				return profiles, err
+			}
+		}
+	}
+	return profiles, nil
+}
+
+func (collector ProfileCollector) Collect(device DeviceConfig) (map[string]string, error) {
+	result := make(map[string]string)
+	p := collector.Profile
+
+	c, err := newSSHCollector(device)
+	if err != nil {
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
+	}
+	defer c.Close()
+
+	if !c.Authenticated {
+		m, _, err := expectMultiRegexp([]*regexp.Regexp{p.UsernamePrompt, p.PasswordPrompt}, c.Receive)
+		if err != nil {
+			return result, fmt.Errorf("Missing login prompt: %s", err.Error())
+		}
+		if m == 0 {
+			c.Send <- device.Config["user"] + "\n"
+			if _, _, err := expectMultiRegexp([]*regexp.Regexp{p.PasswordPrompt}, c.Receive); err != nil {
+				return result, fmt.Errorf("Missing password prompt: %s", err.Error())
+			}
+		}
+		c.Send <- device.Config["pass"] + "\n"
+	}
+
+	multi := []*regexp.Regexp{p.Prompt, p.PasswordPrompt}
+	if p.EnablePrompt != nil {
+		multi = append(multi, p.EnablePrompt)
+	}
+	m, _, err := expectMultiRegexp(multi, c.Receive)
+	if err != nil {
+		return result, fmt.Errorf("Invalid response to password: %s", err.Error())
+	}
+	if m == 1 {
+		return result, fmt.Errorf("Bad username or password.")
+	} else if m == 2 {
+		c.Send <- p.EnableCommand + "\n"
+		m, _, err = expectMultiRegexp([]*regexp.Regexp{p.Prompt, p.EnablePasswordPrompt}, c.Receive)
+		if err != nil {
+			return result, fmt.Errorf("Enable attempt failed: %s", err.Error())
+		}
+		if m == 1 {
+			c.Send <- device.Config["enable"] + "\n"
+			if _, _, err := expectMultiRegexp([]*regexp.Regexp{p.Prompt}, c.Receive); err != nil {
+				return result, fmt.Errorf("Enable attempt failed: %s", err.Error())
+			}
+		}
+	}
+
+	// from here on only the device's exact prompt ends a command
+	prompt, err := c.learnPrompt(p.Prompt)
+	if err != nil {
+		return result, err
+	}
+	for _, command := range p.Setup {
+		c.Send <- command + "\n"
+		if err := expectRegexp(prompt, c.Receive); err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", command, err.Error())
+		}
+	}
+	for _, cmd := range p.Commands {
+		c.Send <- cmd.Command + "\n"
+		output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+		if err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", cmd.Command, err.Error())
+		}
+		result[cmd.Name] = p.clean(cleanCommandOutput(output, cmd.Command))
+	}
+
+	c.Send <- p.Exit + "\n"
+
+	return result, nil
+}
+
+// clean drops the output lines matching any of the profile's cleanup regexes
+func (p *Profile) clean(output string) string {
+	if len(p.Cleanup) == 0 {
+		return output
+	}
+	lines := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		keep := true
+		for _, re := range p.Cleanup {
+			if re.MatchString(line) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,142 @@
+package sweet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testProfileSection = map[string]string{
+	"prompt":            `#\s*$`,
+	"enable-prompt":     `>\s*$`,
+	"setup":             "terminal length 0",
+	"command.config":    "show running-config",
+	"command.version":   "show version",
+	"cleanup.1":         `^! Last change`,
+	"cleanup.uptime":    `uptime is`,
+	"unrelated-setting": "ignored",
+}
+
+func TestProfileCollect(t *testing.T) {
+	profile, err := LoadProfile("dellos6", testProfileSection)
+	if err != nil {
+		t.Fatalf("Unable to load profile: %s", err.Error())
+	}
+	shell := &fakeShell{
+		Prompt: "sw1>",
+		Responses: map[string]string{
+			"enable":              "Password:",
+			"show running-config": "! Last change at 10:00:00\r\nhostname sw1\r\n!\r\n",
+			"show version":        "Dell Networking OS 6.5\r\nsw1 uptime is 3 days\r\n",
+		},
+	}
+	shell.Hooks = map[string]func(){
+		"enable":   func() { shell.Prompt = "" },
+		"enablepw": func() { shell.Prompt = "sw1#" },
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Config["enable"] = "enablepw"
+
+	result, err := newProfileCollector(profile).Collect(d)
+	if err != nil {
+		t.Fatalf("Profile collection failed: %s", err.Error())
+	}
+	expected := map[string]string{
+		"config":  "hostname sw1\n!",
+		"version": "Dell Networking OS 6.5",
+	}
+	for name, want := range expected {
+		if result[name] != want {
+			t.Errorf("Bad %s result: %q", name, result[name])
+		}
+	}
+	if strings.Join(shell.Received[:4], ",") != "enable,enablepw,,terminal length 0" {
+		t.Errorf("Bad command order: %v", shell.Received)
+	}
+}
+
+func TestProfileOverTelnet(t *testing.T) {
+	profile, err := LoadProfile("simple", map[string]string{"prompt": `\$ $`, "command.config": "cat config", "exit": "logout"})
+	if err != nil {
+		t.Fatalf("Unable to load profile: %s", err.Error())
+	}
+	server := &telnetTestServer{
+		UserPrompt: "login: ",
+		User:       "sweet",
+		Pass:       "sweetpw",
+		Shell:      &fakeShell{Prompt: "box $ ", Responses: map[string]string{"cat config": "option=1\r\n"}},
+	}
+	addr := server.start(t)
+
+	result, err := newProfileCollector(profile).Collect(testTelnetDevice(addr))
+	if err != nil {
+		t.Fatalf("Profile collection over telnet failed: %s", err.Error())
+	}
+	if result["config"] != "option=1" {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+}
+
+func TestProfileLoadErrors(t *testing.T) {
+	for _, section := range []map[string]string{
+		{"command.config": "show config"},
+		{"prompt": "#$"},
+		{"prompt": "(", "command.config": "show config"},
+		{"prompt": "#$", "command.config": "show config", "cleanup.1": "["},
+		{"prompt": "", "command.config": "show config"},
+		{"prompt": "#$", "command.config": "show config", "username-prompt": ""},
+		{"prompt": "#$", "command.config": "show config", "password-prompt": ""},
+		{"prompt": "#$", "command.config": "show config", "enable-password-prompt": ""},
+	} {
+		if _, err := LoadProfile("bad", section); err == nil {
+			t.Errorf("Expected error loading profile %v", section)
+		}
+	}
+}
+
+func TestProfileSetupOrder(t *testing.T) {
+	profile, err := LoadProfile("ordered", map[string]string{
+		"prompt":         "#$",
+		"enable-prompt":  "",
+		"command.config": "show config",
+		"setup.10":       "banner exec x, y",
+		"setup.2":        "terminal width 512",
+		"setup.1":        "terminal length 0",
+	})
+	if err != nil {
+		t.Fatalf("Unable to load profile: %s", err.Error())
+	}
+	if strings.Join(profile.Setup, "|") != "terminal length 0|terminal width 512|banner exec x, y" {
+		t.Errorf("Bad setup commands: %q", profile.Setup)
+	}
+	if profile.EnablePrompt != nil || profile.UsernamePrompt == nil || profile.EnablePasswordPrompt == nil {
+		t.Errorf("Empty enable-prompt should only turn off enable")
+	}
+}
+
+func TestProfileLoadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sweet-profiles")
+	if err != nil {
+		t.Fatalf("Unable to create temp dir: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	conf := "[profile:edgeswitch]\nprompt = #\\s*$\nsetup = terminal length 0\ncommand.config = show running-config\n\n[not-a-profile]\nprompt = x\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "ubiquiti.conf"), []byte(conf), 0644); err != nil {
+		t.Fatalf("Unable to write profile: %s", err.Error())
+	}
+	ioutil.WriteFile(filepath.Join(dir, "README"), []byte("not a profile"), 0644)
+
+	profiles, err := LoadProfileDir(dir)
+	if err != nil {
+		t.Fatalf("Unable to load profiles: %s", err.Error())
+	}
+	if len(profiles) != 1 || profiles["edgeswitch"] == nil {
+		t.Fatalf("Bad profiles: %v", profiles)
+	}
+	if profiles["edgeswitch"].Commands[0] != (ProfileCommand{"config", "show running-config"}) {
+		t.Errorf("Bad profile commands: %v", profiles["edgeswitch"].Commands)
+	}
+}
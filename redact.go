@@ -0,0 +1,201 @@
+package sweet
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redactKeyFile keys the placeholder hashes. It stays in the workspace but out
+// of git, so pushed configs can't be checked against guessed secrets.
+const redactKeyFile = "redact.key"
+
+var (
+	redactKeyLock sync.Mutex
+	redactKey     []byte
+)
+
+// Each rule redacts the first of its groups that matched, or the whole match
+// when it has none.
+var ciscoSecrets = []*regexp.Regexp{
+	regexp.MustCompile(`\b(?:secret|password|passwd)(?: (?:[0-9]+|sha512|encrypted|clear))? (\S+)`),
+	regexp.MustCompile(`^\s*snmp-server community (\S+)`),
+	regexp.MustCompile(`^\s*snmp-server host \S+ (?:vrf \S+ )?(?:informs |traps )?(?:version (?:1|2c|3(?: auth| noauth| priv)?) )?(\S+)`),
+	regexp.MustCompile(`^\s*snmp-server user \S+ .*\bauth (?:md5|sha\S*) (\S+)`),
+	regexp.MustCompile(`\bpriv (?:aes(?:-| )?[0-9]+ |3?des )?(\S+)`),
+	regexp.MustCompile(`^\s*(?:tacacs-server|radius-server|tacacs|radius) .*\bkey(?: [0-9])? (\S+)`),
+	ciscoServerKey,
+	regexp.MustCompile(`\b(?:key-string|ospf\S* authentication-key|message-digest-key [0-9]+ md5)(?: password| clear| encrypted)?(?: [0-9])? (\S+)`),
+	regexp.MustCompile(`^\s*ntp authentication-key [0-9]+ \S+(?: encrypted| clear)? (\S+)`),
+	regexp.MustCompile(`\bpre-shared-key (?:address|hostname) .*\bkey (?:[0-9] )?(\S+)`),
+	regexp.MustCompile(`\bpre-shared-key (?:local|remote) (?:[0-9] )?(\S+)`),
+	regexp.MustCompile(`\bpre-shared-key(?: [0-9])? (\S+)$`),
+	regexp.MustCompile(`\bcrypto isakmp key(?: [0-9])? (\S+)`),
+}
+
+// the key of e.g. a "tacacs server" block, as " key 7 08224550" or " key s3cret"
+var ciscoServerKey = regexp.MustCompile(`^\s*(?:server-)?key (?:[0-9] )?(\S+)$`)
+
+// redactSkip keeps rules out of config sections they would misread, by the
+// section's unindented first line: " key 1" in a key chain is a key id.
+var redactSkip = map[*regexp.Regexp]*regexp.Regexp{
+	ciscoServerKey: regexp.MustCompile(`^key chain `),
+}
+
+// redactSecrets are the built-in secret patterns for each method
+var redactSecrets = map[string][]*regexp.Regexp{
+	"cisco": ciscoSecrets,
+	"nxos":  ciscoSecrets,
+	"iosxr": ciscoSecrets,
+	"eos":   ciscoSecrets,
+	"junos": {
+		regexp.MustCompile(`\$[0-9]\$[^";<\s]+`),
+		regexp.MustCompile(`\bcommunity (\S+) \{`),
+		regexp.MustCompile(`^set snmp community (\S+)`),
+	},
+	"screenos": {
+		regexp.MustCompile(`\b(?:password|preshare|secret|community)\s+"([^"]*)"`),
+	},
+	"fortios": {
+		regexp.MustCompile(`^\s*set (?:passwd|password|psksecret|secret|key|auth-pwd|priv-pwd) (?:"([^"]*)"|(\S+))`),
+	},
+	"panos": {
+		regexp.MustCompile(`<(?:phash|password|secret|key|private-key|pre-shared-key|community|authentication-password|privacy-password|auth-password|priv-password)>([^<]+)</`),
+	},
+	"routeros": {
+		regexp.MustCompile(`\b(?:password|secret|shared-secret|authentication-key|pre-shared-key|wpa-pre-shared-key|wpa2-pre-shared-key|passphrase|private-key)=(?:"([^"]*)"|(\S+))`),
+	},
+	"procurve": {
+		regexp.MustCompile(`\b(?:sha1|sha-256|plaintext|ciphertext) (?:"([^"]*)"|(\S+))`),
+		regexp.MustCompile(`\b(?:community|key) "([^"]*)"`),
+	},
+	"aoscx": {
+		regexp.MustCompile(`\b(?:plaintext|ciphertext) (\S+)`),
+		regexp.MustCompile(`^\s*snmp-server community (\S+)`),
+	},
+}
+
+// redactor hides secrets in collection results
+type redactor struct {
+	rules []*regexp.Regexp
+	key   []byte
+}
+
+//// Set up redaction for a device, or nil when it is turned off with "redact = false"
+func newRedactor(device DeviceConfig, Opts *SweetOptions) (*redactor, error) {
+	if device.Config["redact"] == "false" {
+		return nil, nil
+	}
+	r := &redactor{rules: append([]*regexp.Regexp{}, redactSecrets[device.Method]...)}
+	for _, pattern := range []string{Opts.RedactRegex, device.Config["redact-regex"]} {
+		if len(pattern) == 0 {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Bad redact-regex setting %s for host %s: %s", pattern, device.Hostname, err.Error())
+		}
+		r.rules = append(r.rules, re)
+	}
+	if len(r.rules) == 0 {
+		return nil, nil
+	}
+	key, err := loadRedactKey()
+	if err != nil {
+		return nil, err
+	}
+	r.key = key
+	return r, nil
+}
+
+// loadRedactKey reads the workspace's placeholder key, creating it on first use
+func loadRedactKey() ([]byte, error) {
+	redactKeyLock.Lock()
+	defer redactKeyLock.Unlock()
+	if redactKey != nil {
+		return redactKey, nil
+	}
+	key, err := ioutil.ReadFile(redactKeyFile)
+	if os.IsNotExist(err) {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("Unable to create %s: %s", redactKeyFile, err.Error())
+		}
+		if err := ioutil.WriteFile(redactKeyFile, key, 0600); err != nil {
+			return nil, fmt.Errorf("Unable to create %s: %s", redactKeyFile, err.Error())
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("Unable to read %s: %s", redactKeyFile, err.Error())
+	}
+	if err := excludeFromGit(redactKeyFile); err != nil {
+		return nil, err
+	}
+	redactKey = key
+	return key, nil
+}
+
+// placeholder stands in for a secret: the same secret always gets the same
+// placeholder, so a changed secret still shows up in diffs.
+func (r *redactor) placeholder(secret string) string {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(secret))
+	return "<redacted:" + hex.EncodeToString(mac.Sum(nil))[:12] + ">"
+}
+
+// redact replaces the secrets in every line of a collection result
+func (r *redactor) redact(output string) string {
+	if r == nil {
+		return output
+	}
+	lines := strings.Split(output, "\n")
+	section := ""
+	for i, line := range lines {
+		cr := strings.HasSuffix(line, "\r")
+		line = strings.TrimSuffix(line, "\r")
+		if len(line) > 0 && line[0] != ' ' && line[0] != '\t' {
+			section = line
+		}
+		for _, re := range r.rules {
+			if skip, ok := redactSkip[re]; ok && skip.MatchString(section) {
+				continue
+			}
+			line = r.redactLine(line, re)
+		}
+		if cr {
+			line += "\r"
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *redactor) redactLine(line string, re *regexp.Regexp) string {
+	matches := re.FindAllStringSubmatchIndex(line, -1)
+	if matches == nil {
+		return line
+	}
+	result := ""
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		for g := 2; g < len(m); g += 2 {
+			if m[g] >= 0 {
+				start, end = m[g], m[g+1]
+				break
+			}
+		}
+		if end <= start || start < last || strings.HasPrefix(line[start:end], "<redacted:") {
+			continue
+		}
+		result += line[last:start] + r.placeholder(line[start:end])
+		last = end
+	}
+	return result + line[last:]
+}
@@ -0,0 +1,151 @@
+package sweet
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func testRedactor(t *testing.T, device DeviceConfig, Opts *SweetOptions) *redactor {
+	inTempWorkspace(t)
+	r, err := newRedactor(device, Opts)
+	if err != nil {
+		t.Fatalf("Error setting up redaction: %s", err.Error())
+	}
+	return r
+}
+
+func TestRedactCisco(t *testing.T) {
+	device := DeviceConfig{Hostname: "sw1", Method: "cisco", Config: map[string]string{}}
+	r := testRedactor(t, device, &SweetOptions{})
+	config := strings.Join([]string{
+		"service password-encryption",
+		"enable secret 5 $1$mERr$hx5rVt7rPNoS4wqbXKX7m0",
+		"username admin privilege 15 password 7 0822455D0A16",
+		"snmp-server community s3cretComm RO",
+		"snmp-server host 10.1.1.1 version 2c hostComm",
+		"tacacs-server host 10.1.1.2 key 7 045802150C2E",
+		"key chain OSPF",
+		" key 1",
+		"  key-string 7 13061E010803",
+		"crypto isakmp key vpnPSK address 10.2.2.2",
+		"line vty 0 4",
+		" password 7 094F471A1A0A",
+		"hostname sw1",
+	}, "\r\n")
+	result := r.redact(config)
+	for _, secret := range []string{"$1$mERr$hx5rVt7rPNoS4wqbXKX7m0", "0822455D0A16", "s3cretComm", "hostComm", "045802150C2E", "13061E010803", "vpnPSK", "094F471A1A0A"} {
+		if strings.Contains(result, secret) {
+			t.Errorf("Secret %s not redacted:\n%s", secret, result)
+		}
+	}
+	for _, kept := range []string{"service password-encryption\r\n", "enable secret 5 <redacted:", "username admin privilege 15 password 7 <redacted:", "snmp-server community <redacted:", "> RO\r\n", "\r\n key 1\r\n", "hostname sw1"} {
+		if !strings.Contains(result, kept) {
+			t.Errorf("Redacted config missing %q:\n%s", kept, result)
+		}
+	}
+}
+
+func TestRedactCiscoKeys(t *testing.T) {
+	device := DeviceConfig{Hostname: "sw1", Method: "cisco", Config: map[string]string{}}
+	r := testRedactor(t, device, &SweetOptions{})
+	cases := []struct{ line, secret, kept string }{
+		{"crypto keyring VPN\n pre-shared-key address 10.1.1.1 key 0 mypsk", "mypsk", " pre-shared-key address 10.1.1.1 key 0 <redacted:"},
+		{"crypto ikev2 keyring VPN\n peer branch\n  pre-shared-key local mylocal", "mylocal", "  pre-shared-key local <redacted:"},
+		{"crypto ikev2 keyring VPN\n peer branch\n  pre-shared-key remote X", "remote X", "  pre-shared-key remote <redacted:"},
+		{"tacacs server TAC1\n address ipv4 10.1.1.2\n key 7 08224550", "08224550", " key 7 <redacted:"},
+		{"radius server RAD1\n address ipv4 10.1.1.3\n key 0 12345678", "12345678", " key 0 <redacted:"},
+		{"key chain OSPF\n key 1\n  key-string 7 13061E010803", "13061E010803", "\n key 1\n"},
+		{"ntp authentication-key 1 md5 0822455D0A16 7", "0822455D0A16", "ntp authentication-key 1 md5 <redacted:"},
+		{"interface Gi0/1\n ip ospf authentication-key 7 045802150C2E", "045802150C2E", " ip ospf authentication-key 7 <redacted:"},
+		{"snmp-server host 10.1.1.1 vrf Mgmt-intf version 2c public", "public", "snmp-server host 10.1.1.1 vrf Mgmt-intf version 2c <redacted:"},
+		{"snmp-server host 10.1.1.1 vrf Mgmt-intf informs version 3 priv v3user", "v3user", "snmp-server host 10.1.1.1 vrf Mgmt-intf informs version 3 priv <redacted:"},
+		{"snmp-server host 10.1.1.1 traps version 3 auth v3auth", "v3auth", "snmp-server host 10.1.1.1 traps version 3 auth <redacted:"},
+	}
+	for _, c := range cases {
+		result := r.redact(c.line)
+		if strings.Contains(result, c.secret) || !strings.Contains(result, c.kept) {
+			t.Errorf("Bad redaction of %q: %q", c.line, result)
+		}
+	}
+}
+
+func TestRedactPlaceholdersStable(t *testing.T) {
+	device := DeviceConfig{Hostname: "sw1", Method: "cisco", Config: map[string]string{}}
+	r := testRedactor(t, device, &SweetOptions{})
+	first := r.redact("snmp-server community one RO")
+	if again := r.redact("snmp-server community one RO"); again != first {
+		t.Errorf("Placeholder changed between runs: %s then %s", first, again)
+	}
+	if changed := r.redact("snmp-server community two RO"); changed == first {
+		t.Errorf("Changed secret should change its placeholder: %s", changed)
+	}
+	if !regexp.MustCompile(`^snmp-server community <redacted:[0-9a-f]{12}> RO$`).MatchString(first) {
+		t.Errorf("Bad placeholder: %s", first)
+	}
+}
+
+func TestRedactOtherMethods(t *testing.T) {
+	cases := []struct{ method, output, secret string }{
+		{"junos", `encrypted-password "$6$abc$defghijk"; ## SECRET-DATA`, "$6$abc$defghijk"},
+		{"junos", `set system radius-server 10.1.1.1 secret "$9$Hk5FCtOIhrvL"`, "$9$Hk5FCtOIhrvL"},
+		{"junos", "community junosComm {", "junosComm"},
+		{"routeros", `/user add name=backup password="my pass" group=read`, "my pass"},
+		{"panos", "<phash>$1$fhcaubja$Bm2jGW0RK</phash>", "$1$fhcaubja$Bm2jGW0RK"},
+		{"procurve", `snmp-server community "hpComm" operator`, "hpComm"},
+		{"screenos", `set admin password "nKVUM2rwMUzPcrkG5sWIHdCtqkAibn"`, "nKVUM2rwMUzPcrkG5sWIHdCtqkAibn"},
+		{"aoscx", "user admin group administrators password ciphertext AQBapVrq", "AQBapVrq"},
+	}
+	inTempWorkspace(t)
+	for _, c := range cases {
+		device := DeviceConfig{Hostname: "dev1", Method: c.method, Config: map[string]string{}}
+		r, _ := newRedactor(device, &SweetOptions{})
+		if result := r.redact(c.output); strings.Contains(result, c.secret) || !strings.Contains(result, "<redacted:") {
+			t.Errorf("Bad %s redaction: %s", c.method, result)
+		}
+	}
+}
+
+func TestRedactSettings(t *testing.T) {
+	device := DeviceConfig{Hostname: "lb1", Method: "external", Config: map[string]string{}}
+	r := testRedactor(t, device, &SweetOptions{})
+	if r != nil {
+		t.Errorf("Methods without secret patterns should not be redacted")
+	}
+
+	device.Config["redact-regex"] = `^ssl key (\S+)`
+	r, err := newRedactor(device, &SweetOptions{RedactRegex: `token=\w+`})
+	if err != nil {
+		t.Fatalf("Error setting up redaction: %s", err.Error())
+	}
+	result := r.redact("ssl key abc123\nurl /api?token=xyz789&x=1")
+	if strings.Contains(result, "abc123") || strings.Contains(result, "xyz789") || !strings.HasSuffix(result, "&x=1") {
+		t.Errorf("Bad custom redaction: %s", result)
+	}
+
+	device.Config["redact-regex"] = "(unclosed"
+	if _, err := newRedactor(device, &SweetOptions{}); err == nil || !strings.Contains(err.Error(), "Bad redact-regex setting") {
+		t.Errorf("Expected a bad redact-regex error but got %v", err)
+	}
+
+	device = DeviceConfig{Hostname: "sw1", Method: "cisco", Config: map[string]string{"redact": "false"}}
+	if r, _ := newRedactor(device, &SweetOptions{}); r.redact("snmp-server community public RO") != "snmp-server community public RO" {
+		t.Errorf("redact = false should keep secrets")
+	}
+}
+
+func TestRedactKeyFile(t *testing.T) {
+	inTempWorkspace(t)
+	redactKeyLock.Lock()
+	redactKey = nil
+	redactKeyLock.Unlock()
+	key, err := loadRedactKey()
+	if err != nil {
+		t.Fatalf("Error loading key: %s", err.Error())
+	}
+	saved, err := ioutil.ReadFile(redactKeyFile)
+	if err != nil || string(saved) != string(key) || len(key) != 32 {
+		t.Errorf("Key not saved to the workspace")
+	}
+}
@@ -12,6 +12,7 @@ func runReporter(Opts *SweetOptions) error {
 	Opts.LogInfo("Starting reporter.")
 	changeReport := ""
 	changeDiffs := ""
+	hostKeyAlert := false
 
 	// print changes to log
 	for _, device := range Opts.Devices {
@@ -31,6 +32,9 @@ func runReporter(Opts *SweetOptions) error {
 					}
 				}
 			}
+		} else if stat.State == StateHostKeyChanged {
+			hostKeyAlert = true
+			changeReport += fmt.Sprintf("%s: WARNING: SSH HOST KEY CHANGED - POSSIBLE MAN-IN-THE-MIDDLE: %s\n", device.Hostname, stat.ErrorMessage)
 		} else {
 			changeReport += fmt.Sprintf("%s: error: %s\n", device.Hostname, stat.ErrorMessage)
 		}
@@ -45,6 +49,9 @@ func runReporter(Opts *SweetOptions) error {
 			return fmt.Errorf("Error getting my hostname: %s", err.Error())
 		}
 		emailSubject := fmt.Sprintf("Change notification from Sweet on %s", hostname)
+		if hostKeyAlert {
+			emailSubject = "WARNING: SSH host key changed - " + emailSubject
+		}
 		err = sendEmail(Opts, emailSubject, changeReport+changeDiffs)
 		if err != nil {
 			return fmt.Errorf("Error sending notification email: %s", err.Error())
@@ -0,0 +1,93 @@
+package sweet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RouterOS prompts look like "[admin@MikroTik] > "
+var routerOSPromptEnd = regexp.MustCompile(`\] >\s*$`)
+
+// a repeated login prompt after a bad password
+var routerOSLoginPrompt = regexp.MustCompile(`ogin:\s*$`)
+
+// "/export" starts with e.g. "# oct/18/2026 10:00:00 by RouterOS 6.48.6", which changes every run
+var routerOSExportHeader = regexp.MustCompile(`^# .* by RouterOS .*$`)
+
+type RouterOS struct {
+}
+
+func newRouterOSCollector() Collector {
+	return RouterOS{}
+}
+
+func (collector RouterOS) Collect(device DeviceConfig) (map[string]string, error) {
+	result := make(map[string]string)
+
+	// the "+ct" login suffix turns off colors and terminal auto-detection
+	login := device
+	login.Config = make(map[string]string, len(device.Config))
+	for k, v := range device.Config {
+		login.Config[k] = v
+	}
+	if !strings.Contains(login.Config["user"], "+") {
+		login.Config["user"] += "+ct"
+		// jump hosts default to the device user, which doesn't take the suffix
+		if len(login.Config["jumpuser"]) == 0 {
+			login.Config["jumpuser"] = device.Config["user"]
+		}
+	}
+
+	c, err := newSSHCollector(login)
+	if err != nil {
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
+	}
+	defer c.Close()
+
+	if err := c.login(login); err != nil {
+		return result, err
+	}
+	m, output, err := expectMultiRegexp([]*regexp.Regexp{routerOSPromptEnd, passwordPromptEnd, routerOSLoginPrompt}, c.Receive)
+	if err != nil {
+		return result, fmt.Errorf("Invalid response to password: %s", err.Error())
+	}
+	if m != 0 {
+		return result, fmt.Errorf("Bad username or password.")
+	}
+	prompt := promptRegexp(lastLine(output))
+
+	export := "/export terse"
+	if device.Config["show-sensitive"] == "true" {
+		export += " show-sensitive"
+	}
+	commands := []struct{ name, command string }{
+		{"config", export},
+		{"version", "/system resource print"},
+	}
+	for _, cmd := range commands {
+		c.Send <- cmd.command + "\n"
+		output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+		if err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", cmd.command, err.Error())
+		}
+		result[cmd.name] = routerOSCleanOutput(cleanCommandOutput(output, cmd.command))
+	}
+
+	c.Send <- "/quit\n"
+
+	return result, nil
+}
+
+// routerOSCleanOutput drops the timestamped export header and trailing whitespace on each line
+func routerOSCleanOutput(output string) string {
+	lines := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r ")
+		if routerOSExportHeader.MatchString(line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
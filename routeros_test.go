@@ -0,0 +1,80 @@
+package sweet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRouterOSCollect(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "\x1b[9999B[sweet@gw1] > ",
+		Responses: map[string]string{
+			"/export terse": "# oct/18/2026 10:00:00 by RouterOS 6.48.6\r\n# software id = ABCD-1234\r\n" +
+				"/interface bridge add name=bridge1\r\n/ip address add address=192.0.2.1/24 interface=bridge1\r\n",
+			"/system resource print": "  \x1b[m\x1b[32m uptime\x1b[m: 2w3d\r\n  version: 6.48.6 (long-term)\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet+ct", "sweetpw"), shell)
+
+	result, err := newRouterOSCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("RouterOS collection failed: %s", err.Error())
+	}
+	expected := map[string]string{
+		"config":  "# software id = ABCD-1234\n/interface bridge add name=bridge1\n/ip address add address=192.0.2.1/24 interface=bridge1",
+		"version": "   uptime: 2w3d\n  version: 6.48.6 (long-term)",
+	}
+	for name, want := range expected {
+		if result[name] != want {
+			t.Errorf("Bad %s result: %q", name, result[name])
+		}
+	}
+}
+
+func TestRouterOSShowSensitive(t *testing.T) {
+	shell := &fakeShell{Prompt: "[sweet@gw1] > "}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet+ct", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Config["show-sensitive"] = "true"
+
+	if _, err := newRouterOSCollector().Collect(d); err != nil {
+		t.Fatalf("RouterOS collection failed: %s", err.Error())
+	}
+	if shell.Received[0] != "/export terse show-sensitive" {
+		t.Errorf("Bad export command: %v", shell.Received)
+	}
+}
+
+func TestRouterOSThroughJumpHost(t *testing.T) {
+	shell := &fakeShell{Prompt: "[sweet@gw1] > "}
+	deviceAddr := startTestSSHServer(t, passwordServerConfig("sweet+ct", "sweetpw"), shell)
+	bastionAddr := startTestSSHServer(t, passwordServerConfig("sweet", "jumppw"), &fakeShell{})
+	d := testSSHDevice(deviceAddr)
+	d.Config["jumphost"] = bastionAddr
+	d.Config["jumppass"] = "jumppw"
+
+	if _, err := newRouterOSCollector().Collect(d); err != nil {
+		t.Fatalf("RouterOS collection through jump host failed: %s", err.Error())
+	}
+	if d.Config["user"] != "sweet" || len(d.Config["jumpuser"]) > 0 {
+		t.Errorf("Device config should not change: %v", d.Config)
+	}
+}
+
+func TestRouterOSPromptInConfig(t *testing.T) {
+	export := "/ip firewall filter add chain=forward comment=\"[lan] > wan\" action=accept\r\n" +
+		strings.Repeat("/ip address add address=192.0.2.1/24 interface=bridge1\r\n", 8)
+	shell := &fakeShell{
+		Prompt:    "[sweet@gw1] > ",
+		Responses: map[string]string{"/export terse": export},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet+ct", "sweetpw"), shell)
+
+	result, err := newRouterOSCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("RouterOS collection failed: %s", err.Error())
+	}
+	if result["config"] != strings.TrimSpace(strings.Replace(export, "\r\n", "\n", -1)) {
+		t.Errorf("Config cut short at a prompt-like line: %q", result["config"])
+	}
+}
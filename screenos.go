@@ -0,0 +1,66 @@
+package sweet
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ScreenOS prompts look like "ns5gt-> "
+var screenOSPromptEnd = regexp.MustCompile(`->\s*$`)
+
+// asked on exit when the config was changed, e.g. "Configuration modified, save? [y]/n "
+var screenOSSavePrompt = regexp.MustCompile(`save\? .*$`)
+
+type ScreenOS struct {
+}
+
+func newScreenOSCollector() Collector {
+	return ScreenOS{}
+}
+
+func (collector ScreenOS) Collect(device DeviceConfig) (map[string]string, error) {
+	result := make(map[string]string)
+
+	c, err := newSSHCollector(device)
+	if err != nil {
+		return result, fmt.Errorf("Error connecting to %s: %w", device.Hostname, err)
+	}
+	defer c.Close()
+
+	if err := c.login(device); err != nil {
+		return result, err
+	}
+	m, output, err := expectMultiRegexp([]*regexp.Regexp{screenOSPromptEnd, passwordPromptEnd}, c.Receive)
+	if err != nil {
+		return result, fmt.Errorf("Invalid response to password: %s", err.Error())
+	}
+	if m == 1 {
+		return result, fmt.Errorf("Bad username or password.")
+	}
+	prompt := promptRegexp(lastLine(output))
+	c.Send <- "set console page 0\n"
+	if err := expectRegexp(prompt, c.Receive); err != nil {
+		return result, fmt.Errorf("Command 'set console page 0' failed: %s", err.Error())
+	}
+
+	commands := []struct{ name, command string }{
+		{"config", "get config"},
+		{"version", "get system version"},
+	}
+	for _, cmd := range commands {
+		c.Send <- cmd.command + "\n"
+		output, err := expectSaveRegexpTimeout(prompt, c.Receive, device.CommandTimeout)
+		if err != nil {
+			return result, fmt.Errorf("Command '%s' failed: %s", cmd.command, err.Error())
+		}
+		result[cmd.name] = cleanCommandOutput(output, cmd.command)
+	}
+
+	// "set console page" counts as a change, so don't save it on the way out
+	c.Send <- "exit\n"
+	if m, _, _ := expectMultiRegexp([]*regexp.Regexp{screenOSSavePrompt, prompt}, c.Receive); m == 0 {
+		c.Send <- "n\n"
+	}
+
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package sweet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScreenOSCollect(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "ns5gt-> ",
+		Responses: map[string]string{
+			"get config":         "Total Config size 2048:\r\nset clock timezone 0\r\nset hostname ns5gt\r\n",
+			"get system version": "Software Version: 5.4.0r28.0, Type: Firewall+VPN\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newScreenOSCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("ScreenOS collection failed: %s", err.Error())
+	}
+	expected := map[string]string{
+		"config":  "Total Config size 2048:\nset clock timezone 0\nset hostname ns5gt",
+		"version": "Software Version: 5.4.0r28.0, Type: Firewall+VPN",
+	}
+	for name, want := range expected {
+		if result[name] != want {
+			t.Errorf("Bad %s result: %q", name, result[name])
+		}
+	}
+	if shell.Received[0] != "set console page 0" {
+		t.Errorf("Paging was not disabled first: %v", shell.Received)
+	}
+}
+
+func TestScreenOSPromptInConfig(t *testing.T) {
+	config := "set policy id 1 name \"dmz->wan\" from \"DMZ\" to \"Untrust\" \"Any\" \"Any\" \"ANY\" permit\r\n" +
+		strings.Repeat("set interface ethernet1 zone Untrust\r\n", 10)
+	shell := &fakeShell{
+		Prompt:    "ns5gt-> ",
+		Responses: map[string]string{"get config": config},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newScreenOSCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("ScreenOS collection failed: %s", err.Error())
+	}
+	if result["config"] != strings.TrimSpace(strings.Replace(config, "\r\n", "\n", -1)) {
+		t.Errorf("Config cut short at a prompt-like line: %q", result["config"])
+	}
+}
@@ -0,0 +1,380 @@
+package sweet
+
+import (
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	sshTermWidth  = 511
+	sshTermHeight = 24
+)
+
+// SSHCollector is an interactive session with a device. Collectors read
+// device output from Receive and write keystrokes to Send.
+type SSHCollector struct {
+	Receive chan string
+	Send    chan string
+	// Authenticated is set when the transport logged in on its own, so the
+	// collector shouldn't wait for a password prompt.
+	Authenticated bool
+	closers       []io.Closer
+	closeOnce     sync.Once
+	done          chan struct{}
+	transcript    *transcript // set with the debug-transcript option
+}
+
+//// Open an interactive session to a device over its configured transport
+func newSSHCollector(device DeviceConfig) (*SSHCollector, error) {
+	if device.Config["transport"] == "telnet" {
+		return newTelnetCollector(device)
+	}
+
+	c := new(SSHCollector)
+	c.Receive = make(chan string)
+	c.Send = make(chan string)
+	c.done = make(chan struct{})
+
+	client, closers, err := sshConnect(device)
+	if err != nil {
+		return c, err
+	}
+	c.closers = closers
+
+	session, err := client.NewSession()
+	if err != nil {
+		c.Close()
+		return c, err
+	}
+	c.closers = append([]io.Closer{session}, c.closers...)
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 38400,
+		ssh.TTY_OP_OSPEED: 38400,
+	}
+	if err := session.RequestPty("vt100", sshTermHeight, sshTermWidth, modes); err != nil {
+		c.Close()
+		return c, fmt.Errorf("pty request failed: %s", err.Error())
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		c.Close()
+		return c, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		c.Close()
+		return c, err
+	}
+	if err := session.Shell(); err != nil {
+		c.Close()
+		return c, fmt.Errorf("shell request failed: %s", err.Error())
+	}
+	c.Authenticated = true
+	if err := c.openTranscript(device); err != nil {
+		c.Close()
+		return c, err
+	}
+	c.start(stdout, stdin, device.Timeout)
+
+	return c, nil
+}
+
+// sshConnect logs in to a device, returning the client along with everything
+// that must be closed to tear it down, innermost first.
+func sshConnect(device DeviceConfig) (*ssh.Client, []io.Closer, error) {
+	config, agentConn, err := sshClientConfig(device)
+	if agentConn != nil {
+		defer agentConn.Close() // only needed while authenticating
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	addr := sshAddress(device)
+	conn, jumps, err := dialDevice(device, addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := sshHandshake(conn, addr, config, device.Timeout)
+	if err != nil {
+		for _, closer := range jumps {
+			closer.Close()
+		}
+		return nil, nil, err
+	}
+	return client, append([]io.Closer{client}, jumps...), nil
+}
+
+// sshHandshake logs in over an established connection
+func sshHandshake(conn net.Conn, addr string, config *ssh.ClientConfig, timeout time.Duration) (*ssh.Client, error) {
+	var hostKeyErr error
+	check := config.HostKeyCallback
+	hostKeyConfig := *config
+	hostKeyConfig.HostKeyCallback = func(addr string, remote net.Addr, key ssh.PublicKey) error {
+		hostKeyErr = check(addr, remote, key)
+		return hostKeyErr
+	}
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout)) // not supported on tunnelled connections
+	}
+	ncc, chans, reqs, err := ssh.NewClientConn(conn, addr, &hostKeyConfig)
+	if err != nil {
+		conn.Close()
+		if hostKeyErr != nil {
+			return nil, hostKeyErr // keep the error type, the ssh package only keeps the text
+		}
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return ssh.NewClient(ncc, chans, reqs), nil
+}
+
+// login answers the username and password prompts of transports that
+// don't authenticate on their own. It is a no-op for SSH sessions.
+func (c *SSHCollector) login(device DeviceConfig) error {
+	if c.Authenticated {
+		return nil
+	}
+	m, err := expectMulti([]string{"sername:", "ogin:", "assword:"}, c.Receive)
+	if err != nil {
+		return fmt.Errorf("Missing login prompt: %s", err.Error())
+	}
+	if m != "assword:" {
+		c.Send <- device.Config["user"] + "\n"
+		if err := expect("assword:", c.Receive); err != nil {
+			return fmt.Errorf("Missing password prompt: %s", err.Error())
+		}
+	}
+	c.Send <- device.Config["pass"] + "\n"
+	return nil
+}
+
+// learnPrompt sends a blank line and reads back the device's exact prompt,
+// i.e. the line ending in suffix, so commands can be ended by it alone.
+func (c *SSHCollector) learnPrompt(suffix *regexp.Regexp) (*regexp.Regexp, error) {
+	c.Send <- "\n"
+	_, output, err := expectMultiRegexp([]*regexp.Regexp{suffix}, c.Receive)
+	if err != nil {
+		return nil, fmt.Errorf("Missing prompt: %s", err.Error())
+	}
+	return promptRegexp(lastLine(output)), nil
+}
+
+// openTranscript starts recording the session if the device asks for it
+func (c *SSHCollector) openTranscript(device DeviceConfig) error {
+	if device.Config["debug-transcript"] != "true" {
+		return nil
+	}
+	t, err := newTranscript(device)
+	if err != nil {
+		return err
+	}
+	c.transcript = t
+	return nil
+}
+
+// start pumps the session streams through the Receive and Send channels.
+// The session is torn down after timeout so a stuck collector can't leak it.
+func (c *SSHCollector) start(r io.Reader, w io.Writer, timeout time.Duration) {
+	if timeout > 0 {
+		time.AfterFunc(timeout, c.Close)
+	}
+
+	// set once anything has been sent, see below
+	var sent int32
+
+	go func() {
+		defer close(c.Receive)
+		tail := ""
+		for {
+			str, err := readChunk(r)
+			if err != nil {
+				c.Close()
+				return
+			}
+			if c.transcript != nil {
+				c.transcript.record("<", str)
+			}
+			// page through output ourselves in case the device ignored "terminal length 0".
+			// Prompts before we've sent anything are login banners, like ProCurve's
+			// "Press any key to continue", which the collector answers itself.
+			if atomic.LoadInt32(&sent) == 0 {
+				tail = ""
+			} else {
+				tail += str
+			}
+			if len(tail) > pagerTailSize {
+				tail = tail[len(tail)-pagerTailSize:]
+			}
+			if pagerPrompt.MatchString(tail) {
+				tail = ""
+				select {
+				case c.Send <- " ":
+				case <-c.done:
+					return
+				}
+			}
+			select {
+			case c.Receive <- str:
+			case <-c.done:
+				return
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			select {
+			case command := <-c.Send:
+				if c.transcript != nil {
+					c.transcript.record(">", command)
+				}
+				atomic.StoreInt32(&sent, 1)
+				if _, err := io.WriteString(w, command); err != nil {
+					c.Close()
+					return
+				}
+			case <-c.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close tears down the session and its connection.
+func (c *SSHCollector) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		for _, closer := range c.closers {
+			closer.Close()
+		}
+		if c.transcript != nil {
+			c.transcript.Close()
+		}
+	})
+}
+
+func sshAddress(device DeviceConfig) string {
+	port := "22"
+	if len(device.Config["port"]) > 0 {
+		port = device.Config["port"]
+	}
+	return net.JoinHostPort(device.Target, port)
+}
+
+//// Build SSH client settings from device options
+func sshClientConfig(device DeviceConfig) (*ssh.ClientConfig, io.Closer, error) {
+	config := &ssh.ClientConfig{
+		User:    device.Config["user"],
+		Timeout: device.Timeout,
+	}
+
+	if len(device.Config["ssh-ciphers"]) > 0 {
+		config.Ciphers = splitList(device.Config["ssh-ciphers"])
+	}
+	if len(device.Config["ssh-kex"]) > 0 {
+		config.KeyExchanges = splitList(device.Config["ssh-kex"])
+	}
+	if len(device.Config["ssh-macs"]) > 0 {
+		config.MACs = splitList(device.Config["ssh-macs"])
+	}
+
+	auth, agentConn, err := sshAuthMethods(device)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.Auth = auth
+
+	config.HostKeyCallback, config.HostKeyAlgorithms, err = hostKeyCheck(device, sshAddress(device))
+	if err != nil {
+		return nil, agentConn, err
+	}
+	return config, agentConn, nil
+}
+
+//// Offer agent, public-key, password and keyboard-interactive logins
+func sshAuthMethods(device DeviceConfig) ([]ssh.AuthMethod, io.Closer, error) {
+	methods := []ssh.AuthMethod{}
+	var agentConn net.Conn
+
+	if device.Config["use-agent"] == "true" {
+		sock := os.Getenv("SSH_AUTH_SOCK")
+		if len(sock) == 0 {
+			return nil, nil, fmt.Errorf("use-agent is set but SSH_AUTH_SOCK is not")
+		}
+		var err error
+		agentConn, err = net.Dial("unix", sock)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Unable to connect to SSH agent: %s", err.Error())
+		}
+		methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+	}
+
+	if len(device.Config["key"]) > 0 {
+		signer, err := sshKeySigner(device.Config["key"], device.Config["key-passphrase"])
+		if err != nil {
+			if agentConn != nil {
+				agentConn.Close()
+			}
+			return nil, nil, err
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	pass := device.Config["pass"]
+	if len(pass) > 0 {
+		methods = append(methods, ssh.Password(pass))
+		methods = append(methods, ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+			answers := make([]string, len(questions))
+			for i := range questions {
+				answers[i] = pass
+			}
+			return answers, nil
+		}))
+	}
+	if agentConn == nil {
+		return methods, nil, nil
+	}
+	return methods, agentConn, nil
+}
+
+// load a private key file, decrypting it if a passphrase is given
+func sshKeySigner(path, passphrase string) (ssh.Signer, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read key %s: %s", path, err.Error())
+	}
+	var signer ssh.Signer
+	if len(passphrase) > 0 {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(raw, []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse key %s: %s", path, err.Error())
+	}
+	return signer, nil
+}
+
+// splitList splits a comma separated option into its trimmed values
+func splitList(s string) []string {
+	list := []string{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) > 0 {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,435 @@
+package sweet
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"io"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeShell is a scripted device CLI: each command line gets its canned
+// response followed by the prompt.
+type fakeShell struct {
+	Banner    string
+	Prompt    string
+	Responses map[string]string
+	Hooks     map[string]func() // run after a command is received, before replying
+	Received  []string
+}
+
+func (f *fakeShell) serve(rw io.ReadWriter) {
+	rw.Write([]byte(f.Banner + f.Prompt))
+	scanner := bufio.NewScanner(rw)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		f.Received = append(f.Received, line)
+		if line == "exit" || line == "quit" {
+			return
+		}
+		if hook, ok := f.Hooks[line]; ok {
+			hook()
+		}
+		out := line + "\r\n"
+		if resp, ok := f.Responses[line]; ok {
+			out += resp
+		}
+		rw.Write([]byte(out + f.Prompt))
+	}
+}
+
+// exec answers a non-interactive command with its canned response, failing
+// like a missing program when there isn't one.
+func (f *fakeShell) exec(channel ssh.Channel, command string) {
+	f.Received = append(f.Received, command)
+	status := uint32(0)
+	if resp, ok := f.Responses[command]; ok {
+		channel.Write([]byte(resp))
+	} else {
+		channel.Stderr().Write([]byte(command + ": command not found\n"))
+		status = 127
+	}
+	channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+	channel.Close()
+}
+
+// startTestSSHServer runs an SSH server for one connection and returns its address.
+func startTestSSHServer(t *testing.T, config *ssh.ServerConfig, shell *fakeShell) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unable to generate host key: %s", err.Error())
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("Unable to create host key signer: %s", err.Error())
+	}
+	config.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %s", err.Error())
+	}
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		_, chans, reqs, err := ssh.NewServerConn(conn, config)
+		if err != nil {
+			conn.Close()
+			return
+		}
+		go ssh.DiscardRequests(reqs)
+		for newChannel := range chans {
+			if newChannel.ChannelType() == "direct-tcpip" {
+				go forwardTestChannel(newChannel)
+				continue
+			}
+			if newChannel.ChannelType() != "session" {
+				newChannel.Reject(ssh.UnknownChannelType, "unsupported")
+				continue
+			}
+			channel, requests, err := newChannel.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				for req := range requests {
+					if req.Type == "exec" {
+						var payload struct{ Command string }
+						ssh.Unmarshal(req.Payload, &payload)
+						req.Reply(true, nil)
+						go shell.exec(channel, payload.Command)
+						continue
+					}
+					req.Reply(req.Type == "pty-req" || req.Type == "shell", nil)
+					if req.Type == "shell" {
+						go func() {
+							shell.serve(channel)
+							channel.Close()
+						}()
+					}
+				}
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+// forwardTestChannel lets the test server act as a jump host
+func forwardTestChannel(newChannel ssh.NewChannel) {
+	var target struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	conn, err := net.Dial("tcp", net.JoinHostPort(target.Host, fmt.Sprint(target.Port)))
+	if err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	go func() {
+		io.Copy(conn, channel)
+		conn.Close()
+	}()
+	io.Copy(channel, conn)
+	channel.Close()
+}
+
+func passwordServerConfig(user, pass string) *ssh.ServerConfig {
+	return &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, p []byte) (*ssh.Permissions, error) {
+			if c.User() == user && string(p) == pass {
+				return nil, nil
+			}
+			return nil, errTestAuth
+		},
+	}
+}
+
+var errTestAuth = errors.New("bad credentials")
+
+func testSSHDevice(addr string) DeviceConfig {
+	host, port, _ := net.SplitHostPort(addr)
+	d := DeviceConfig{Hostname: "router1", Target: host, Timeout: 5 * time.Second, CommandTimeout: 200 * time.Millisecond}
+	d.Config = map[string]string{"user": "sweet", "pass": "sweetpw", "insecure": "true", "port": port}
+	return d
+}
+
+func TestSSHCollectorPassword(t *testing.T) {
+	shell := &fakeShell{Banner: "Welcome\r\n", Prompt: "router1#"}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	c, err := newSSHCollector(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("Error connecting: %s", err.Error())
+	}
+	defer c.Close()
+	if !c.Authenticated {
+		t.Errorf("Native SSH session should be authenticated")
+	}
+	if err := expect("router1#", c.Receive); err != nil {
+		t.Errorf("Missing prompt: %s", err.Error())
+	}
+}
+
+func TestSSHCollectorBadPassword(t *testing.T) {
+	shell := &fakeShell{Prompt: "router1#"}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "otherpw"), shell)
+
+	if _, err := newSSHCollector(testSSHDevice(addr)); err == nil {
+		t.Errorf("Expected authentication failure")
+	}
+}
+
+func TestSSHCollectorKeyboardInteractive(t *testing.T) {
+	config := &ssh.ServerConfig{
+		KeyboardInteractiveCallback: func(c ssh.ConnMetadata, challenge ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			answers, err := challenge("", "", []string{"Password: "}, []bool{false})
+			if err != nil {
+				return nil, err
+			}
+			if len(answers) == 1 && answers[0] == "sweetpw" {
+				return nil, nil
+			}
+			return nil, errTestAuth
+		},
+	}
+	shell := &fakeShell{Prompt: "router1#"}
+	addr := startTestSSHServer(t, config, shell)
+
+	c, err := newSSHCollector(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("Error connecting: %s", err.Error())
+	}
+	defer c.Close()
+	if err := expect("router1#", c.Receive); err != nil {
+		t.Errorf("Missing prompt: %s", err.Error())
+	}
+}
+
+func TestSSHCollectorTimeoutCloses(t *testing.T) {
+	shell := &fakeShell{Prompt: "router1#"}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	d := testSSHDevice(addr)
+	d.Timeout = 500 * time.Millisecond
+
+	c, err := newSSHCollector(d)
+	if err != nil {
+		t.Fatalf("Error connecting: %s", err.Error())
+	}
+	done := make(chan error)
+	go func() {
+		done <- expect("never-matches", c.Receive)
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Errorf("Expected closed connection error")
+		}
+	case <-time.After(3 * time.Second):
+		t.Errorf("Session was not closed after device timeout")
+	}
+}
+
+func TestCiscoOverSSH(t *testing.T) {
+	shell := &fakeShell{
+		Prompt: "router1#",
+		Responses: map[string]string{
+			"show running-config": "Building configuration...\r\n\r\nhostname router1\r\n",
+			"show version":        "Cisco IOS Software\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+
+	result, err := newCiscoCollector().Collect(testSSHDevice(addr))
+	if err != nil {
+		t.Fatalf("Collection failed: %s", err.Error())
+	}
+	if !strings.HasPrefix(result["config"], "hostname router1") {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+	if !strings.Contains(result["version"], "Cisco IOS Software") {
+		t.Errorf("Bad version result: %q", result["version"])
+	}
+}
+
+func TestSSHSplitList(t *testing.T) {
+	list := splitList(" aes128-cbc, 3des-cbc,,")
+	if len(list) != 2 || list[0] != "aes128-cbc" || list[1] != "3des-cbc" {
+		t.Errorf("Bad list: %v", list)
+	}
+}
+
+func keyServerConfig(signer ssh.Signer) *ssh.ServerConfig {
+	authorized := string(signer.PublicKey().Marshal())
+	return &ssh.ServerConfig{
+		PublicKeyCallback: func(c ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == authorized {
+				return nil, nil
+			}
+			return nil, errTestAuth
+		},
+	}
+}
+
+func writeTestKey(t *testing.T, passphrase string) (string, ssh.Signer) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %s", err.Error())
+	}
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	if len(passphrase) > 0 {
+		block, err = x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte(passphrase), x509.PEMCipherAES128)
+		if err != nil {
+			t.Fatalf("Unable to encrypt key: %s", err.Error())
+		}
+	}
+	path := filepath.Join(t.TempDir(), "id_rsa")
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("Unable to write key: %s", err.Error())
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("Unable to create signer: %s", err.Error())
+	}
+	return path, signer
+}
+
+func TestSSHCollectorPublicKey(t *testing.T) {
+	path, signer := writeTestKey(t, "")
+	shell := &fakeShell{Prompt: "router1#"}
+	addr := startTestSSHServer(t, keyServerConfig(signer), shell)
+	d := testSSHDevice(addr)
+	d.Config["pass"] = ""
+	d.Config["key"] = path
+
+	c, err := newSSHCollector(d)
+	if err != nil {
+		t.Fatalf("Error connecting with key: %s", err.Error())
+	}
+	defer c.Close()
+	if err := expect("router1#", c.Receive); err != nil {
+		t.Errorf("Missing prompt: %s", err.Error())
+	}
+}
+
+func TestSSHCollectorKeyPassphrase(t *testing.T) {
+	path, signer := writeTestKey(t, "keypw")
+	shell := &fakeShell{Prompt: "router1#"}
+	addr := startTestSSHServer(t, keyServerConfig(signer), shell)
+	d := testSSHDevice(addr)
+	d.Config["key"] = path
+	d.Config["key-passphrase"] = "keypw"
+
+	c, err := newSSHCollector(d)
+	if err != nil {
+		t.Fatalf("Error connecting with encrypted key: %s", err.Error())
+	}
+	c.Close()
+
+	d.Config["key-passphrase"] = "wrong"
+	if _, err := newSSHCollector(d); err == nil {
+		t.Errorf("Expected error with wrong key passphrase")
+	}
+}
+
+func TestSSHCollectorAgent(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %s", err.Error())
+	}
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatalf("Unable to add key to agent: %s", err.Error())
+	}
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatalf("Unable to listen for agent: %s", err.Error())
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go agent.ServeAgent(keyring, conn)
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", sock)
+
+	signer, _ := ssh.NewSignerFromKey(key)
+	shell := &fakeShell{Prompt: "router1#"}
+	addr := startTestSSHServer(t, keyServerConfig(signer), shell)
+	d := testSSHDevice(addr)
+	d.Config["pass"] = ""
+	d.Config["use-agent"] = "true"
+
+	c, err := newSSHCollector(d)
+	if err != nil {
+		t.Fatalf("Error connecting with agent: %s", err.Error())
+	}
+	defer c.Close()
+	if err := expect("router1#", c.Receive); err != nil {
+		t.Errorf("Missing prompt: %s", err.Error())
+	}
+}
+
+func TestSSHCollectorPager(t *testing.T) {
+	client, device := net.Pipe()
+	defer device.Close()
+	c := &SSHCollector{Receive: make(chan string), Send: make(chan string), done: make(chan struct{})}
+	c.closers = []io.Closer{client}
+	c.start(client, client, 0)
+	defer c.Close()
+
+	keys := make(chan string, 2)
+	go func() {
+		buf := make([]byte, 64)
+		device.Read(buf) // the command
+		device.Write([]byte("show running-config\r\nhostname router1\r\n --More-- "))
+		n, _ := device.Read(buf)
+		keys <- string(buf[:n])
+		device.Write([]byte("\x08\x08\x08\x08\x08\x08\x08\x08\x08\x08          \x08\x08\x08\x08\x08\x08\x08\x08\x08\x08interface Gi0/1\r\n---(more 50%)---"))
+		n, _ = device.Read(buf)
+		keys <- string(buf[:n])
+		device.Write([]byte("\r                                        \r description uplink\r\nrouter1#"))
+	}()
+
+	c.Send <- "show running-config\n"
+	output, err := expectSaveRegexpTimeout(promptRegexp("router1#"), c.Receive, time.Second)
+	if err != nil {
+		t.Fatalf("Error reading paged output: %s", err.Error())
+	}
+	if k1, k2 := <-keys, <-keys; k1 != " " || k2 != " " {
+		t.Errorf("Pager prompts were not answered with a space: %q %q", k1, k2)
+	}
+	if out := cleanCommandOutput(output, "show running-config"); out != "hostname router1\ninterface Gi0/1\n description uplink" {
+		t.Errorf("Bad scrubbed output: %q", out)
+	}
+}
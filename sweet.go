@@ -3,13 +3,13 @@ package sweet
 // sweet.go: network device backups and change alerts for the 21st century - inspired by RANCID.
 
 import (
+	"errors"
 	"fmt"
-	"github.com/kr/pty"
-	"io"
 	"io/ioutil"
 	"log/syslog"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -26,6 +26,9 @@ type DeviceConfig struct {
 	Timeout        time.Duration
 	CommandTimeout time.Duration
 	Config         map[string]string
+
+	// hides secrets in results and debug transcripts, set up by collectDevice
+	redactor *redactor
 }
 
 type DeviceStatusState int
@@ -35,6 +38,7 @@ const (
 	StateError
 	StateTimeout
 	StateSuccess
+	StateHostKeyChanged
 )
 
 type ConfigDiff struct {
@@ -51,6 +55,8 @@ type DeviceStatus struct {
 	Configs      map[string]string
 	Diffs        map[string]ConfigDiff
 	ErrorMessage string
+	LastChanged  time.Time // when the device's results were last committed
+	Transcript   bool      // the collection was recorded under transcripts/
 }
 type Status struct {
 	Status map[string]DeviceStatus
@@ -58,17 +64,13 @@ type Status struct {
 }
 
 // ReportWebData options for formatting web status page.
-type WebReport struct {
-	DeviceStatus
+type ReportWebData struct {
 	Class          string
 	CSSID          string
 	EnableDiffLink bool
 	EnableConfLink bool
-}
 
-type SSHCollector struct {
-	Receive chan string
-	Send    chan string
+	EnableTranscriptLink bool
 }
 
 type SweetOptions struct {
@@ -91,12 +93,42 @@ type SweetOptions struct {
 	Syslog        *syslog.Writer
 	Devices       []DeviceConfig
 	Status        *Status
+
+	// SSH key logins for devices without their own key settings
+	DefaultKey           string
+	DefaultKeyPassphrase string
+	UseAgent             bool
+
+	// bastion settings for devices without their own jump settings
+	JumpHost string
+	JumpUser string
+	JumpKey  string
+
+	// config-defined collectors, used with "method = profile:NAME"
+	Profiles map[string]*Profile
+
+	// record device sessions under transcripts/ in the workspace
+	DebugTranscript bool
+
+	// drop matching lines from every device's results, on top of ignore-regex per device
+	IgnoreRegex string
+
+	// redact matches from every device's results, on top of redact-regex per device
+	RedactRegex string
 }
 
 type Collector interface {
 	Collect(device DeviceConfig) (map[string]string, error)
 }
 
+// transcriptNote points a failed collection at its session transcript, if one was recorded
+func transcriptNote(device DeviceConfig, Opts *SweetOptions) string {
+	if !hasTranscript(device) {
+		return ""
+	}
+	return fmt.Sprintf(" (session transcript: %s)", filepath.Join(Opts.Workspace, transcriptPath(device.Hostname)))
+}
+
 //// Kickoff collector runs
 func RunCollectors(Opts *SweetOptions) {
 	collectorSlots := make(chan bool, Opts.Concurrency)
@@ -107,15 +139,18 @@ func RunCollectors(Opts *SweetOptions) {
 		go func() {
 			for _, device := range Opts.Devices {
 				collectorSlots <- true
+				lastChanged := Opts.Status.Get(device.Hostname).LastChanged
 				status := DeviceStatus{}
 				status.Device = device
 				status.When = time.Now()
 				status.State = StatePending
+				status.LastChanged = lastChanged
 				Opts.Status.Set(status)
 
 				Opts.LogInfo(fmt.Sprintf("Starting collector: %s", device.Hostname))
 				status = collectDevice(device, Opts)
 				Opts.LogInfo(fmt.Sprintf("Finished collector: %s", device.Hostname))
+				status.LastChanged = lastChanged
 				Opts.Status.Set(status)
 			}
 			Opts.LogInfo(fmt.Sprintf("All %d collectors finished.", len(Opts.Devices)))
@@ -125,6 +160,7 @@ func RunCollectors(Opts *SweetOptions) {
 			if err := commitChanges(Opts); err != nil {
 				Opts.LogFatal(err.Error())
 			}
+			updateLastChanged(Opts)
 			if err := runReporter(Opts); err != nil {
 				Opts.LogFatal(err.Error())
 			}
@@ -153,6 +189,14 @@ func collectDevice(device DeviceConfig, Opts *SweetOptions) DeviceStatus {
 	status.Device = device
 	status.When = time.Now()
 
+	// fill in defaults on a copy of the settings, the web status pages read
+	// the originals while devices are being collected
+	config := make(map[string]string, len(device.Config))
+	for k, v := range device.Config {
+		config[k] = v
+	}
+	device.Config = config
+
 	if len(device.Method) == 0 {
 		if len(Opts.DefaultMethod) == 0 {
 			status.State = StateError
@@ -193,11 +237,41 @@ func collectDevice(device DeviceConfig, Opts *SweetOptions) DeviceStatus {
 		}
 		device.Config["user"] = Opts.DefaultUser
 	}
+	_, ok = device.Config["key"]
+	if !ok && len(Opts.DefaultKey) > 0 {
+		device.Config["key"] = Opts.DefaultKey
+	}
+	_, ok = device.Config["key-passphrase"]
+	if !ok && len(Opts.DefaultKeyPassphrase) > 0 {
+		device.Config["key-passphrase"] = Opts.DefaultKeyPassphrase
+	}
+	_, ok = device.Config["use-agent"]
+	if !ok && Opts.UseAgent {
+		device.Config["use-agent"] = "true"
+	}
+	_, ok = device.Config["jumphost"]
+	if !ok && len(Opts.JumpHost) > 0 {
+		device.Config["jumphost"] = Opts.JumpHost
+	}
+	_, ok = device.Config["jumpuser"]
+	if !ok && len(Opts.JumpUser) > 0 {
+		device.Config["jumpuser"] = Opts.JumpUser
+	}
+	_, ok = device.Config["jumpkey"]
+	if !ok && len(Opts.JumpKey) > 0 {
+		device.Config["jumpkey"] = Opts.JumpKey
+	}
+	for _, keyOpt := range []string{"key", "jumpkey", "ca-file"} {
+		if len(device.Config[keyOpt]) > 0 && device.Config[keyOpt][0] != os.PathSeparator {
+			device.Config[keyOpt] = Opts.ExecutableDir + string(os.PathSeparator) + device.Config[keyOpt]
+		}
+	}
+	keyAuth := len(device.Config["key"]) > 0 || device.Config["use-agent"] == "true" || len(device.Config["api-key"]) > 0
 	_, ok = device.Config["pass"]
 	if !ok {
-		if len(Opts.DefaultPass) == 0 {
+		if len(Opts.DefaultPass) == 0 && !keyAuth {
 			status.State = StateError
-			status.ErrorMessage = fmt.Sprintf("No pass specified for %s and default-pass not defined.", device.Hostname)
+			status.ErrorMessage = fmt.Sprintf("No pass or key specified for %s and default-pass not defined.", device.Hostname)
 			return status
 		}
 		device.Config["pass"] = Opts.DefaultPass
@@ -211,9 +285,34 @@ func collectDevice(device DeviceConfig, Opts *SweetOptions) DeviceStatus {
 	if ok {
 		device.Target = device.Config["ip"]
 	}
+	transport, ok := device.Config["transport"]
+	if ok && transport != "ssh" && transport != "telnet" {
+		status.State = StateError
+		status.ErrorMessage = fmt.Sprintf("Unknown transport %s for host %s", transport, device.Hostname)
+		return status
+	}
 	if Opts.Insecure {
 		device.Config["insecure"] = "true"
 	}
+	_, ok = device.Config["debug-transcript"]
+	if !ok && Opts.DebugTranscript {
+		device.Config["debug-transcript"] = "true"
+	}
+	status.Transcript = device.Config["debug-transcript"] == "true"
+
+	ignore, err := newIgnoreFilter(device, Opts)
+	if err != nil {
+		status.State = StateError
+		status.ErrorMessage = err.Error()
+		return status
+	}
+	redact, err := newRedactor(device, Opts)
+	if err != nil {
+		status.State = StateError
+		status.ErrorMessage = err.Error()
+		return status
+	}
+	device.redactor = redact
 
 	var c Collector
 	if device.Method == "cisco" {
@@ -227,6 +326,34 @@ func collectDevice(device DeviceConfig, Opts *SweetOptions) DeviceStatus {
 		c = newExternalCollector()
 	} else if device.Method == "junos" {
 		c = newJunOSCollector()
+	} else if device.Method == "eos" {
+		c = newEOSCollector()
+	} else if device.Method == "nxos" {
+		c = newNXOSCollector()
+	} else if device.Method == "iosxr" {
+		c = newIOSXRCollector()
+	} else if device.Method == "routeros" {
+		c = newRouterOSCollector()
+	} else if device.Method == "fortios" {
+		c = newFortiOSCollector()
+	} else if device.Method == "panos" {
+		c = newPANOSCollector()
+	} else if device.Method == "procurve" {
+		c = newProCurveCollector()
+	} else if device.Method == "aoscx" {
+		c = newAOSCXCollector()
+	} else if device.Method == "screenos" {
+		c = newScreenOSCollector()
+	} else if device.Method == "linux" {
+		c = newLinuxCollector()
+	} else if strings.HasPrefix(device.Method, ProfilePrefix) {
+		profile, ok := Opts.Profiles[strings.TrimPrefix(device.Method, ProfilePrefix)]
+		if !ok {
+			status.State = StateError
+			status.ErrorMessage = fmt.Sprintf("Unknown profile in method: %s", device.Method)
+			return status
+		}
+		c = newProfileCollector(profile)
 	} else {
 		status.State = StateError
 		status.ErrorMessage = fmt.Sprintf("Unknown access method: %s", device.Method)
@@ -249,15 +376,23 @@ func collectDevice(device DeviceConfig, Opts *SweetOptions) DeviceStatus {
 	case <-time.After(Opts.Timeout):
 		status.State = StateError
 		status.ErrorMessage = fmt.Sprintf("collection timeout after %d seconds", int(device.Timeout.Seconds()))
+		status.ErrorMessage += transcriptNote(device, Opts)
 		return status
 	case err := <-e:
 		status.State = StateError
+		var hostKeyErr *HostKeyChangedError
+		if errors.As(err, &hostKeyErr) {
+			status.State = StateHostKeyChanged
+		}
 		status.ErrorMessage = fmt.Sprintf("collection error: %s", err.Error())
+		status.ErrorMessage += transcriptNote(device, Opts)
 		return status
 	}
 
-	// save the collectionResults to the workspace
+	// save the collectionResults to the workspace, minus lines that change on every run and secrets
 	for name, val := range collectionResults {
+		val = redact.redact(ignore.filter(name, val))
+		collectionResults[name] = val
 		Opts.LogInfo(fmt.Sprintf("Saving result: %s %s", device.Hostname, name))
 		err = ioutil.WriteFile(device.Hostname+"-"+cleanName(name), []byte(val), 0644)
 		if err != nil {
@@ -271,55 +406,6 @@ func collectDevice(device DeviceConfig, Opts *SweetOptions) DeviceStatus {
 	return status
 }
 
-func newSSHCollector(device DeviceConfig) (*SSHCollector, error) {
-	c := new(SSHCollector)
-	c.Receive = make(chan string)
-	c.Send = make(chan string)
-
-	var cmd *exec.Cmd
-	_, ok := device.Config["insecure"]
-	if ok && device.Config["insecure"] == "true" {
-		cmd = exec.Command("ssh", "-oStrictHostKeyChecking=no", device.Config["user"]+"@"+device.Target)
-	} else {
-		cmd = exec.Command("ssh", device.Config["user"]+"@"+device.Target)
-	}
-
-	f, err := pty.Start(cmd)
-	if err != nil {
-		return c, err
-	}
-
-	go func() {
-		for {
-			str, err := readChunk(f)
-			if err != nil {
-				close(c.Receive)
-				return
-			}
-			c.Receive <- str
-		}
-	}()
-
-	go func() {
-		for {
-			select {
-			case command, exists := <-c.Send:
-				{
-					if !exists {
-						return
-					}
-					_, err := io.WriteString(f, command)
-					if err != nil {
-						panic("send error")
-					}
-				}
-			}
-		}
-	}()
-
-	return c, nil
-}
-
 func (s *Status) Get(device string) DeviceStatus {
 	defer func() {
 		s.Lock.Unlock()
@@ -332,7 +418,11 @@ func (s *Status) GetAll() map[string]DeviceStatus {
 		s.Lock.Unlock()
 	}()
 	s.Lock.Lock()
-	return s.Status
+	all := make(map[string]DeviceStatus, len(s.Status))
+	for hostname, stat := range s.Status {
+		all[hostname] = stat
+	}
+	return all
 }
 
 func (s *Status) Set(stat DeviceStatus) {
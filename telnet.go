@@ -0,0 +1,183 @@
+package sweet
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"sync"
+)
+
+// telnet protocol bytes (RFC 854 and friends)
+const (
+	telnetSE   = 240
+	telnetSB   = 250
+	telnetWILL = 251
+	telnetWONT = 252
+	telnetDO   = 253
+	telnetDONT = 254
+	telnetIAC  = 255
+
+	telnetOptEcho  = 1
+	telnetOptSGA   = 3
+	telnetOptTType = 24
+	telnetOptNAWS  = 31
+)
+
+// telnetConn strips and answers option negotiation on reads, and escapes
+// IAC and sends CR LF line endings on writes.
+type telnetConn struct {
+	conn    net.Conn
+	lock    sync.Mutex // reads answer negotiation while writes may be in flight
+	state   int
+	command byte
+	sub     []byte
+}
+
+const (
+	telnetStateData = iota
+	telnetStateIAC
+	telnetStateOption
+	telnetStateSub
+	telnetStateSubIAC
+)
+
+//// Open an interactive telnet session to a device
+func newTelnetCollector(device DeviceConfig) (*SSHCollector, error) {
+	c := new(SSHCollector)
+	c.Receive = make(chan string)
+	c.Send = make(chan string)
+	c.done = make(chan struct{})
+
+	port := "23"
+	if len(device.Config["port"]) > 0 {
+		port = device.Config["port"]
+	}
+	conn, jumps, err := dialDevice(device, net.JoinHostPort(device.Target, port))
+	if err != nil {
+		return c, err
+	}
+	c.closers = append([]io.Closer{conn}, jumps...)
+
+	if err := c.openTranscript(device); err != nil {
+		c.Close()
+		return c, err
+	}
+	t := &telnetConn{conn: conn}
+	c.start(t, t, device.Timeout)
+	return c, nil
+}
+
+func (t *telnetConn) Read(p []byte) (int, error) {
+	for {
+		raw := make([]byte, len(p))
+		n, err := t.conn.Read(raw)
+		data := t.filter(raw[:n])
+		if len(data) > 0 || err != nil {
+			return copy(p, data), err
+		}
+	}
+}
+
+func (t *telnetConn) Write(p []byte) (int, error) {
+	out := bytes.Replace(p, []byte{telnetIAC}, []byte{telnetIAC, telnetIAC}, -1)
+	out = bytes.Replace(out, []byte("\r\n"), []byte("\n"), -1)
+	out = bytes.Replace(out, []byte("\n"), []byte("\r\n"), -1)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if _, err := t.conn.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// filter removes telnet commands from raw, replying to negotiations as it goes
+func (t *telnetConn) filter(raw []byte) []byte {
+	data := []byte{}
+	for _, b := range raw {
+		switch t.state {
+		case telnetStateData:
+			if b == telnetIAC {
+				t.state = telnetStateIAC
+			} else {
+				data = append(data, b)
+			}
+		case telnetStateIAC:
+			switch b {
+			case telnetIAC:
+				data = append(data, b)
+				t.state = telnetStateData
+			case telnetWILL, telnetWONT, telnetDO, telnetDONT:
+				t.command = b
+				t.state = telnetStateOption
+			case telnetSB:
+				t.sub = t.sub[:0]
+				t.state = telnetStateSub
+			default:
+				t.state = telnetStateData // NOP, GA and friends
+			}
+		case telnetStateOption:
+			t.negotiate(t.command, b)
+			t.state = telnetStateData
+		case telnetStateSub:
+			if b == telnetIAC {
+				t.state = telnetStateSubIAC
+			} else {
+				t.sub = append(t.sub, b)
+			}
+		case telnetStateSubIAC:
+			if b == telnetSE {
+				t.subnegotiate(t.sub)
+				t.state = telnetStateData
+			} else {
+				t.sub = append(t.sub, b)
+				t.state = telnetStateSub
+			}
+		}
+	}
+	return data
+}
+
+// negotiate agrees to the options a device CLI needs and refuses everything else
+func (t *telnetConn) negotiate(command, option byte) {
+	switch command {
+	case telnetDO:
+		switch option {
+		case telnetOptSGA, telnetOptTType:
+			t.reply(telnetWILL, option)
+		case telnetOptNAWS:
+			t.reply(telnetWILL, option)
+			t.send([]byte{telnetIAC, telnetSB, telnetOptNAWS,
+				byte(sshTermWidth >> 8), byte(sshTermWidth & 0xff),
+				byte(sshTermHeight >> 8), byte(sshTermHeight & 0xff),
+				telnetIAC, telnetSE})
+		default:
+			t.reply(telnetWONT, option)
+		}
+	case telnetWILL:
+		switch option {
+		case telnetOptEcho, telnetOptSGA:
+			t.reply(telnetDO, option)
+		default:
+			t.reply(telnetDONT, option)
+		}
+	}
+}
+
+// subnegotiate answers terminal type requests
+func (t *telnetConn) subnegotiate(sub []byte) {
+	if len(sub) >= 2 && sub[0] == telnetOptTType && sub[1] == 1 { // SEND
+		reply := []byte{telnetIAC, telnetSB, telnetOptTType, 0} // IS
+		reply = append(reply, []byte("VT100")...)
+		t.send(append(reply, telnetIAC, telnetSE))
+	}
+}
+
+func (t *telnetConn) reply(command, option byte) {
+	t.send([]byte{telnetIAC, command, option})
+}
+
+func (t *telnetConn) send(b []byte) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.conn.Write(b)
+}
@@ -0,0 +1,212 @@
+package sweet
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// telnetTestServer plays a telnet device: it negotiates options, asks for a
+// login and then hands the connection to a fakeShell.
+type telnetTestServer struct {
+	UserPrompt  string
+	User, Pass  string
+	Shell       *fakeShell
+	lock        sync.Mutex
+	negotiation []byte
+}
+
+// Read strips the client's telnet commands and keeps them for inspection.
+type telnetTestReader struct {
+	conn   net.Conn
+	server *telnetTestServer
+	inIAC  int
+}
+
+func (r *telnetTestReader) Read(p []byte) (int, error) {
+	for {
+		raw := make([]byte, len(p))
+		n, err := r.conn.Read(raw)
+		data := []byte{}
+		r.server.lock.Lock()
+		for _, b := range raw[:n] {
+			if r.inIAC != 0 || b == telnetIAC {
+				r.server.negotiation = append(r.server.negotiation, b)
+				switch {
+				case b == telnetIAC && r.inIAC == 0:
+					r.inIAC = 2
+				case b == telnetSB:
+					r.inIAC = -1 // until SE
+				case r.inIAC == -1 && b == telnetSE:
+					r.inIAC = 0
+				case r.inIAC > 0:
+					r.inIAC--
+				}
+				continue
+			}
+			data = append(data, b)
+		}
+		r.server.lock.Unlock()
+		if len(data) > 0 || err != nil {
+			return copy(p, data), err
+		}
+	}
+}
+
+type telnetTestConn struct {
+	*telnetTestReader
+	net.Conn
+}
+
+func (c telnetTestConn) Read(p []byte) (int, error) {
+	return c.telnetTestReader.Read(p)
+}
+
+func (s *telnetTestServer) start(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %s", err.Error())
+	}
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte{telnetIAC, telnetDO, telnetOptNAWS, telnetIAC, telnetWILL, telnetOptEcho,
+			telnetIAC, telnetDO, telnetOptTType, telnetIAC, telnetSB, telnetOptTType, 1, telnetIAC, telnetSE,
+			telnetIAC, telnetDO, 39}) // NEW-ENVIRON should be refused
+		rw := telnetTestConn{&telnetTestReader{conn: conn, server: s}, conn}
+
+		buf := make([]byte, 256)
+		if len(s.UserPrompt) > 0 {
+			conn.Write([]byte("\r\nUser Access Verification\r\n\r\n" + s.UserPrompt))
+			if !s.readLine(rw, buf, s.User) {
+				return
+			}
+		}
+		conn.Write([]byte("Password: "))
+		if !s.readLine(rw, buf, s.Pass) {
+			conn.Write([]byte("\r\n% Login invalid\r\n"))
+			return
+		}
+		conn.Write([]byte("\r\n"))
+		s.Shell.serve(rw)
+	}()
+	return l.Addr().String()
+}
+
+func (s *telnetTestServer) readLine(rw telnetTestConn, buf []byte, want string) bool {
+	line := ""
+	for !strings.Contains(line, "\r\n") {
+		n, err := rw.Read(buf)
+		if err != nil {
+			return false
+		}
+		line += string(buf[:n])
+	}
+	return strings.TrimRight(line, "\r\n") == want
+}
+
+func testTelnetDevice(addr string) DeviceConfig {
+	host, port, _ := net.SplitHostPort(addr)
+	d := DeviceConfig{Hostname: "router1", Target: host, Timeout: 5 * time.Second, CommandTimeout: 200 * time.Millisecond}
+	d.Config = map[string]string{"user": "sweet", "pass": "sweetpw", "transport": "telnet", "port": port}
+	return d
+}
+
+func TestTelnetFilter(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	replies := make(chan []byte)
+	go func() {
+		buf := make([]byte, 3)
+		io.ReadFull(server, buf)
+		replies <- buf
+	}()
+	tc := &telnetConn{conn: client}
+	data := tc.filter([]byte{'a', telnetIAC, telnetIAC, 'b', telnetIAC})
+	data = append(data, tc.filter([]byte{telnetWILL, telnetOptSGA, 'c', telnetIAC, 241, 'd'})...)
+	if string(data) != "a\xffbcd" {
+		t.Errorf("Bad filtered data: %q", data)
+	}
+	if reply := <-replies; !bytes.Equal(reply, []byte{telnetIAC, telnetDO, telnetOptSGA}) {
+		t.Errorf("Bad negotiation reply: %v", reply)
+	}
+}
+
+func TestTelnetCisco(t *testing.T) {
+	server := &telnetTestServer{
+		UserPrompt: "Username: ",
+		User:       "sweet",
+		Pass:       "sweetpw",
+		Shell: &fakeShell{
+			Prompt: "router1#",
+			Responses: map[string]string{
+				"show running-config": "Building configuration...\r\n\r\nhostname router1\r\n",
+				"show version":        "Cisco IOS Software\r\n",
+			},
+		},
+	}
+	addr := server.start(t)
+
+	result, err := newCiscoCollector().Collect(testTelnetDevice(addr))
+	if err != nil {
+		t.Fatalf("Collection over telnet failed: %s", err.Error())
+	}
+	if !strings.HasPrefix(result["config"], "hostname router1") {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+
+	server.lock.Lock()
+	negotiation := server.negotiation
+	server.lock.Unlock()
+	for _, want := range [][]byte{
+		{telnetIAC, telnetWILL, telnetOptNAWS},
+		{telnetIAC, telnetDO, telnetOptEcho},
+		{telnetIAC, telnetWILL, telnetOptTType},
+		{telnetIAC, telnetWONT, 39},
+		append([]byte{telnetIAC, telnetSB, telnetOptTType, 0}, []byte("VT100")...),
+	} {
+		if !bytes.Contains(negotiation, want) {
+			t.Errorf("Missing negotiation reply %v in %v", want, negotiation)
+		}
+	}
+}
+
+func TestTelnetJunOS(t *testing.T) {
+	server := &telnetTestServer{
+		UserPrompt: "login: ",
+		User:       "sweet",
+		Pass:       "sweetpw",
+		Shell: &fakeShell{
+			Prompt: "sweet@router1> ",
+			Responses: map[string]string{
+				"show configuration": "version 12.1;\r\nsystem {\r\n}\r\n\r\n",
+			},
+		},
+	}
+	addr := server.start(t)
+
+	result, err := newJunOSCollector().Collect(testTelnetDevice(addr))
+	if err != nil {
+		t.Fatalf("JunOS collection over telnet failed: %s", err.Error())
+	}
+	if !strings.Contains(result["config"], "version 12.1;") {
+		t.Errorf("Bad config result: %q", result["config"])
+	}
+}
+
+func TestTelnetBadPassword(t *testing.T) {
+	server := &telnetTestServer{Pass: "otherpw", Shell: &fakeShell{Prompt: "router1>"}}
+	addr := server.start(t)
+
+	if _, err := newCiscoCollector().Collect(testTelnetDevice(addr)); err == nil {
+		t.Errorf("Expected error for bad telnet password")
+	}
+}
@@ -0,0 +1,133 @@
+package sweet
+
+import (
+	"strconv"
+	"strings"
+)
+
+// renderTerminal plays a terminal stream back the way a screen would show
+// it and returns the plain text: CR returns to the start of the line, BS
+// steps back so later characters overstrike, CSI sequences move the cursor
+// or erase, and other escape and control sequences are dropped.
+func renderTerminal(stream string) string {
+	lines := []string{}
+	line := []rune{}
+	col := 0
+	newline := func() {
+		lines = append(lines, string(line))
+		line = line[:0]
+		col = 0
+	}
+	put := func(r rune) {
+		for len(line) < col {
+			line = append(line, ' ')
+		}
+		if col < len(line) {
+			line[col] = r
+		} else {
+			line = append(line, r)
+		}
+		col++
+	}
+
+	runes := []rune(stream)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			newline()
+		case r == '\r':
+			if i+1 < len(runes) && runes[i+1] == '\n' {
+				continue // CR LF is just a line break
+			}
+			col = 0
+		case r == '\b':
+			if col > 0 {
+				col--
+			}
+		case r == '\t':
+			put(r)
+		case r == 0x1b:
+			i = renderEscape(runes, i, func(final rune, params []int) {
+				n := 1
+				if len(params) > 0 && params[0] > 0 {
+					n = params[0]
+				}
+				switch final {
+				case 'K': // erase to the end of the line, to its start, or all of it
+					if len(params) > 0 && params[0] == 2 {
+						line = line[:0]
+					} else if len(params) > 0 && params[0] == 1 {
+						for j := 0; j < col && j < len(line); j++ {
+							line[j] = ' '
+						}
+					} else if col < len(line) {
+						line = line[:col]
+					}
+				case 'C':
+					col += n
+				case 'D':
+					if col -= n; col < 0 {
+						col = 0
+					}
+				case 'G':
+					col = n - 1
+				case 'H', 'f': // cursor positioning means a new screen line
+					if len(line) > 0 {
+						newline()
+					}
+					if len(params) > 1 && params[1] > 1 {
+						col = params[1] - 1
+					}
+				}
+			})
+		case r < 0x20 || r == 0x7f:
+			// bells, NULs and the like
+		default:
+			put(r)
+		}
+	}
+	lines = append(lines, string(line))
+	return strings.Join(lines, "\n")
+}
+
+// renderEscape skips the escape sequence starting at runes[i], calling csi
+// for CSI sequences, and returns the index of its last rune.
+func renderEscape(runes []rune, i int, csi func(final rune, params []int)) int {
+	if i+1 >= len(runes) {
+		return i
+	}
+	switch runes[i+1] {
+	case '[': // CSI: parameters, then a final byte from @ to ~
+		j := i + 2
+		for j < len(runes) && (runes[j] < '@' || runes[j] > '~') {
+			j++
+		}
+		if j >= len(runes) {
+			return len(runes) - 1
+		}
+		params := []int{}
+		for _, p := range strings.Split(strings.TrimLeft(string(runes[i+2:j]), "?"), ";") {
+			n, _ := strconv.Atoi(p)
+			params = append(params, n)
+		}
+		csi(runes[j], params)
+		return j
+	case ']': // OSC: up to BEL or ESC \
+		for j := i + 2; j < len(runes); j++ {
+			if runes[j] == 0x07 {
+				return j
+			}
+			if runes[j] == 0x1b && j+1 < len(runes) && runes[j+1] == '\\' {
+				return j + 1
+			}
+		}
+		return len(runes) - 1
+	case '(', ')': // character set selection
+		if i+2 < len(runes) {
+			return i + 2
+		}
+		return len(runes) - 1
+	}
+	return i + 1
+}
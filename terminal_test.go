@@ -0,0 +1,33 @@
+package sweet
+
+import (
+	"testing"
+)
+
+func TestRenderTerminal(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"line1\r\nline2\r\n", "line1\nline2\n"},
+		{"\x1b[32mgreen\x1b[0m and \x1b[1;31mred\x1b[m", "green and red"},
+		{"progress 10%\rprogress 100%\r\ndone", "progress 100%\ndone"},
+		{"typo\b\b\b\bfixed", "fixed"},
+		{"abc\b \bd", "abd"},
+		{"hostname sw1\x1b[K\r\n\x1b[24;1H\x1b[2Ksw1# ", "hostname sw1\nsw1# "},
+		{"long line here\r\x1b[Kshort", "short"},
+		{"col\x1b[10Gx", "col      x"},
+		{"\x1b]0;sw1: admin\x07title\x1b(Bset", "titleset"},
+		{"bell\x07 and nul\x00", "bell and nul"},
+		{"split \x1b[", "split "},
+	}
+	for _, test := range tests {
+		if out := renderTerminal(test.in); out != test.want {
+			t.Errorf("renderTerminal(%q) = %q, want %q", test.in, out, test.want)
+		}
+	}
+}
+
+func TestCleanCommandOutputRendersTerminal(t *testing.T) {
+	out := cleanCommandOutput("show version\r\n\x1b[1mVersion\x1b[0m 1.0\r\nUptime: 1d\x08\x08\x08\x08\x08\x08\x08\x08\x08\x08\r\nrouter#", "show version")
+	if out != "Version 1.0\nUptime: 1d" {
+		t.Errorf("Bad cleaned output: %q", out)
+	}
+}
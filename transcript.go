@@ -0,0 +1,142 @@
+package sweet
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// transcriptDir holds session transcripts in the workspace, kept out of git.
+const transcriptDir = "transcripts"
+
+// serialize setting up the transcript directory from concurrent collectors
+var transcriptDirLock sync.Mutex
+
+// the device settings never written to a transcript
+var transcriptSecrets = []string{"pass", "enable", "key-passphrase", "jumppass", "api-key"}
+
+// transcript records everything sent and received in a device session.
+// Output is written a line at a time so the device's secret patterns can be
+// redacted from it like they are from its results.
+type transcript struct {
+	lock       sync.Mutex
+	f          *os.File
+	secrets    []string
+	redact     *redactor
+	pending    string // an incomplete line
+	pendingDir string // and which way it went
+}
+
+func transcriptPath(hostname string) string {
+	return filepath.Join(transcriptDir, cleanName(hostname)+".log")
+}
+
+// hasTranscript reports whether a transcript was recorded for the device
+func hasTranscript(device DeviceConfig) bool {
+	return device.Config["debug-transcript"] == "true" && transcriptExists(device.Hostname)
+}
+
+// transcriptExists reports whether a transcript file is on disk for the host
+func transcriptExists(hostname string) bool {
+	_, err := os.Stat(transcriptPath(hostname))
+	return err == nil
+}
+
+//// Start a new transcript for a device, replacing the one from its last run
+func newTranscript(device DeviceConfig) (*transcript, error) {
+	transcriptDirLock.Lock()
+	err := os.MkdirAll(transcriptDir, 0700)
+	if err == nil {
+		err = excludeFromGit(transcriptDir + "/")
+	}
+	transcriptDirLock.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to set up %s: %s", transcriptDir, err.Error())
+	}
+	f, err := os.OpenFile(transcriptPath(device.Hostname), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create transcript: %s", err.Error())
+	}
+	t := &transcript{f: f, redact: device.redactor}
+	for _, key := range transcriptSecrets {
+		if len(device.Config[key]) > 0 {
+			t.secrets = append(t.secrets, device.Config[key])
+		}
+	}
+	fmt.Fprintf(f, "# sweet session transcript for %s (method %s) started %s\n", device.Hostname, device.Method, time.Now().Format(time.RFC1123))
+	fmt.Fprintf(f, "# \"<\" lines were received from the device and \">\" lines sent to it\n")
+	return t, nil
+}
+
+// record logs the complete lines of one chunk of the session, holding back
+// the rest until the line ends or the session turns around.
+func (t *transcript) record(direction, data string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if direction != t.pendingDir {
+		t.flush()
+		t.pendingDir = direction
+	}
+	t.pending += data
+	if i := strings.LastIndex(t.pending, "\n"); i >= 0 {
+		t.write(direction, t.pending[:i+1])
+		t.pending = t.pending[i+1:]
+	}
+}
+
+func (t *transcript) flush() {
+	if len(t.pending) > 0 {
+		t.write(t.pendingDir, t.pending)
+		t.pending = ""
+	}
+}
+
+// write logs part of the session with passwords masked and secrets redacted
+func (t *transcript) write(direction, data string) {
+	for _, secret := range t.secrets {
+		data = strings.Replace(data, secret, "********", -1)
+	}
+	data = t.redact.redact(data)
+	fmt.Fprintf(t.f, "%s %s %q\n", time.Now().Format("15:04:05.000"), direction, data)
+}
+
+func (t *transcript) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.flush()
+	return t.f.Close()
+}
+
+// excludeFromGit keeps a workspace path out of the commits sweet makes
+func excludeFromGit(pattern string) error {
+	if _, err := os.Stat(".git"); err != nil {
+		return nil // not a git workspace, e.g. in tests
+	}
+	exclude := filepath.Join(".git", "info", "exclude")
+	current, err := ioutil.ReadFile(exclude)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Unable to read %s: %s", exclude, err.Error())
+	}
+	for _, line := range strings.Split(string(current), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(exclude), 0755); err != nil {
+		return fmt.Errorf("Unable to update %s: %s", exclude, err.Error())
+	}
+	f, err := os.OpenFile(exclude, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("Unable to update %s: %s", exclude, err.Error())
+	}
+	defer f.Close()
+	if len(current) > 0 && !strings.HasSuffix(string(current), "\n") {
+		pattern = "\n" + pattern
+	}
+	_, err = f.WriteString(pattern + "\n")
+	return err
+}
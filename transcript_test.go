@@ -0,0 +1,191 @@
+package sweet
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// inTempWorkspace runs the test from an empty directory, like sweet runs from its workspace
+func inTempWorkspace(t *testing.T) string {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Error getting working directory: %s", err.Error())
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Error changing directory: %s", err.Error())
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+	return dir
+}
+
+func TestTranscriptMasksSecrets(t *testing.T) {
+	inTempWorkspace(t)
+	device := DeviceConfig{Hostname: "sw1.example.com", Method: "cisco"}
+	device.Config = map[string]string{"pass": "sweetpw", "enable": "enablepw", "user": "sweet"}
+
+	tr, err := newTranscript(device)
+	if err != nil {
+		t.Fatalf("Error creating transcript: %s", err.Error())
+	}
+	tr.record(">", "sweet\n")
+	tr.record(">", "enablepw\n")
+	tr.record("<", "Password: sweetpw\r\nsw1#")
+	tr.Close()
+
+	raw, err := ioutil.ReadFile(transcriptPath(device.Hostname))
+	if err != nil {
+		t.Fatalf("Error reading transcript: %s", err.Error())
+	}
+	transcript := string(raw)
+	if strings.Contains(transcript, "sweetpw") || strings.Contains(transcript, "enablepw") {
+		t.Errorf("Transcript leaks a password:\n%s", transcript)
+	}
+	if !strings.Contains(transcript, `> "sweet\n"`) || !strings.Contains(transcript, `> "********\n"`) {
+		t.Errorf("Transcript missing sent lines:\n%s", transcript)
+	}
+	if !strings.Contains(transcript, `< "Password: ********\r\n"`) || !strings.Contains(transcript, `< "sw1#"`) {
+		t.Errorf("Transcript missing received data:\n%s", transcript)
+	}
+	if info, err := os.Stat(transcriptPath(device.Hostname)); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Transcript should only be readable by its owner")
+	}
+}
+
+func TestTranscriptRedactsConfig(t *testing.T) {
+	inTempWorkspace(t)
+	shell := &fakeShell{
+		Prompt: "router1#",
+		Responses: map[string]string{
+			"show running-config": "hostname router1\r\nsnmp-server community s3cretComm RO\r\n",
+			"show version":        "Cisco IOS Software\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	device := testSSHDevice(addr)
+	device.Method = "cisco"
+	device.Config["debug-transcript"] = "true"
+	redact, err := newRedactor(device, &SweetOptions{})
+	if err != nil {
+		t.Fatalf("Error setting up redaction: %s", err.Error())
+	}
+	device.redactor = redact
+
+	if _, err := newCiscoCollector().Collect(device); err != nil {
+		t.Fatalf("Collection failed: %s", err.Error())
+	}
+	raw, _ := ioutil.ReadFile(transcriptPath(device.Hostname))
+	if strings.Contains(string(raw), "s3cretComm") || !strings.Contains(string(raw), "snmp-server community <redacted:") {
+		t.Errorf("Transcript should redact config secrets:\n%s", raw)
+	}
+
+	// a secret split across reads is still redacted
+	tr, err := newTranscript(device)
+	if err != nil {
+		t.Fatalf("Error creating transcript: %s", err.Error())
+	}
+	tr.record("<", "snmp-server commu")
+	tr.record("<", "nity spl")
+	tr.record("<", "itComm RO\r\nrouter1#")
+	tr.Close()
+	raw, _ = ioutil.ReadFile(transcriptPath(device.Hostname))
+	if strings.Contains(string(raw), "splitComm") || !strings.Contains(string(raw), `< "router1#"`) {
+		t.Errorf("Transcript should redact secrets split across reads:\n%s", raw)
+	}
+}
+
+func TestTranscriptExcludedFromGit(t *testing.T) {
+	inTempWorkspace(t)
+	if err := os.MkdirAll(".git/info", 0755); err != nil {
+		t.Fatalf("Error creating git dir: %s", err.Error())
+	}
+	if err := ioutil.WriteFile(".git/info/exclude", []byte("# git ls-files --others --exclude-from=.git/info/exclude"), 0644); err != nil {
+		t.Fatalf("Error writing exclude file: %s", err.Error())
+	}
+	for i := 0; i < 2; i++ {
+		if err := excludeFromGit(transcriptDir + "/"); err != nil {
+			t.Fatalf("Error excluding transcripts: %s", err.Error())
+		}
+	}
+	raw, _ := ioutil.ReadFile(".git/info/exclude")
+	if strings.Count(string(raw), "\ntranscripts/\n") != 1 {
+		t.Errorf("Transcripts should be excluded exactly once:\n%s", raw)
+	}
+}
+
+func TestTranscriptSSHSession(t *testing.T) {
+	inTempWorkspace(t)
+	shell := &fakeShell{
+		Prompt: "router1#",
+		Responses: map[string]string{
+			"show running-config": "Building configuration...\r\n\r\nhostname router1\r\n",
+			"show version":        "Cisco IOS Software\r\n",
+		},
+	}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	device := testSSHDevice(addr)
+	device.Config["debug-transcript"] = "true"
+
+	if _, err := newCiscoCollector().Collect(device); err != nil {
+		t.Fatalf("Collection failed: %s", err.Error())
+	}
+	if !hasTranscript(device) {
+		t.Fatalf("No transcript recorded for %s", device.Hostname)
+	}
+	raw, _ := ioutil.ReadFile(transcriptPath(device.Hostname))
+	for _, expected := range []string{`> "show running-config\n"`, `< "`, "hostname router1"} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("Transcript missing %q:\n%s", expected, raw)
+		}
+	}
+
+	addr = startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	device = testSSHDevice(addr)
+	device.Hostname = "router2"
+	device.Config["debug-transcript"] = "false"
+	if _, err := newCiscoCollector().Collect(device); err != nil {
+		t.Fatalf("Collection failed: %s", err.Error())
+	}
+	if _, err := os.Stat(transcriptPath(device.Hostname)); err == nil {
+		t.Errorf("Transcript recorded without debug-transcript")
+	}
+}
+
+func TestTranscriptWeb(t *testing.T) {
+	inTempWorkspace(t)
+	device := DeviceConfig{Hostname: "sw1.example.com", Config: map[string]string{"debug-transcript": "true"}}
+	tr, err := newTranscript(device)
+	if err != nil {
+		t.Fatalf("Error creating transcript: %s", err.Error())
+	}
+	tr.record("<", "sw1#")
+	tr.Close()
+
+	Opts := &SweetOptions{Workspace: "/var/sweet", Status: &Status{Status: make(map[string]DeviceStatus)}}
+	Opts.Status.Set(DeviceStatus{Device: device, State: StateError, ErrorMessage: "collection error: EOF", Transcript: true})
+	Opts.Status.Set(DeviceStatus{Device: DeviceConfig{Hostname: "sw2.example.com"}, State: StateError})
+
+	if note := transcriptNote(device, Opts); note != " (session transcript: /var/sweet/transcripts/sw1.example.com.log)" {
+		t.Errorf("Bad transcript note: %q", note)
+	}
+	reports := buildReports(Opts.Status.GetAll())
+	if !reports[0].Web.EnableTranscriptLink || reports[1].Web.EnableTranscriptLink {
+		t.Errorf("Only sw1 should link a transcript")
+	}
+
+	w := httptest.NewRecorder()
+	webTranscript(w, httptest.NewRequest("GET", "/transcripts/sw1.example.com", nil), Opts)
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `< "sw1#"`) {
+		t.Errorf("Bad transcript page: %d %s", w.Code, w.Body.String())
+	}
+	for _, path := range []string{"/transcripts/sw2.example.com", "/transcripts/../sweet.conf"} {
+		w = httptest.NewRecorder()
+		webTranscript(w, httptest.NewRequest("GET", path, nil), Opts)
+		if w.Code != 404 {
+			t.Errorf("Expected 404 for %s but got %d", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package sweet
+
+import (
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report holds one device's row on the web status page.
+type Report struct {
+	Device        DeviceConfig
+	CollectedTime time.Time
+	ChangedTime   time.Time
+	StatusMessage string
+	Added         int
+	Removed       int
+	Diff          string
+	Web           ReportWebData
+}
+
+// WebIndex is the data passed to the index template.
+type WebIndex struct {
+	Title      string
+	MyHostname string
+	Now        string
+	Devices    []Report
+}
+
+func (r Report) CollectedTimeFormatted() string {
+	return timeAgo(r.CollectedTime)
+}
+
+func (r Report) ChangedTimeFormatted() string {
+	return timeAgo(r.ChangedTime)
+}
+
+//// Run the HTTP status dashboard
+func RunWebserver(Opts *SweetOptions) {
+	indexTmpl, err := loadIndexTemplate()
+	if err != nil {
+		Opts.LogFatal(fmt.Sprintf("Error loading web template: %s", err.Error()))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		webIndex(w, r, Opts, indexTmpl)
+	})
+	mux.HandleFunc("/static/", webStatic)
+	mux.HandleFunc("/configs/", func(w http.ResponseWriter, r *http.Request) {
+		webConfigs(w, r, Opts)
+	})
+	mux.HandleFunc("/transcripts/", func(w http.ResponseWriter, r *http.Request) {
+		webTranscript(w, r, Opts)
+	})
+	mux.HandleFunc(apiPrefix, func(w http.ResponseWriter, r *http.Request) {
+		webAPI(w, r, Opts)
+	})
+	mux.HandleFunc(apiPrefix+"/", func(w http.ResponseWriter, r *http.Request) {
+		webAPI(w, r, Opts)
+	})
+
+	Opts.LogInfo(fmt.Sprintf("Starting web status server on %s", Opts.HttpListen))
+	if err := http.ListenAndServe(Opts.HttpListen, mux); err != nil {
+		Opts.LogFatal(fmt.Sprintf("Web status server failed: %s", err.Error()))
+	}
+}
+
+func loadIndexTemplate() (*template.Template, error) {
+	raw, err := Asset("tmpl/index.html")
+	if err != nil {
+		return nil, err
+	}
+	return template.New("index").Parse(string(raw))
+}
+
+//// Render the device status table
+func webIndex(w http.ResponseWriter, r *http.Request, Opts *SweetOptions, tmpl *template.Template) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	data := WebIndex{
+		Title:      "Status",
+		MyHostname: hostname,
+		Now:        time.Now().Format("15:04:05 MST"),
+		Devices:    buildReports(Opts.Status.GetAll()),
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		Opts.LogErr(fmt.Sprintf("Error rendering web status page: %s", err.Error()))
+	}
+}
+
+//// Serve bundled css and js
+func webStatic(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	asset, err := Asset(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if ctype := mime.TypeByExtension(filepath.Ext(name)); len(ctype) > 0 {
+		w.Header().Set("Content-Type", ctype)
+	}
+	w.Write(asset)
+}
+
+//// Show the most recently collected configs for a single device
+func webConfigs(w http.ResponseWriter, r *http.Request, Opts *SweetOptions) {
+	hostname := strings.TrimPrefix(r.URL.Path, "/configs/")
+	stat, ok := Opts.Status.GetAll()[hostname]
+	if !ok || len(stat.Configs) < 1 {
+		http.NotFound(w, r)
+		return
+	}
+	names := make([]string, 0, len(stat.Configs))
+	for name := range stat.Configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, name := range names {
+		fmt.Fprintf(w, "---- %s %s (collected %s):\n", hostname, name, stat.When.Format(time.RFC1123))
+		fmt.Fprintf(w, "%s\n\n", stat.Configs[name])
+	}
+}
+
+//// Show the debug session transcript for a single device
+func webTranscript(w http.ResponseWriter, r *http.Request, Opts *SweetOptions) {
+	hostname := strings.TrimPrefix(r.URL.Path, "/transcripts/")
+	stat, ok := Opts.Status.GetAll()[hostname]
+	if !ok || !stat.Transcript || !transcriptExists(hostname) {
+		http.NotFound(w, r)
+		return
+	}
+	raw, err := ioutil.ReadFile(transcriptPath(hostname))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(raw)
+}
+
+// buildReports turns device status into sorted dashboard rows
+func buildReports(statuses map[string]DeviceStatus) []Report {
+	hostnames := make([]string, 0, len(statuses))
+	for hostname := range statuses {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+
+	reports := make([]Report, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		stat := statuses[hostname]
+		r := Report{}
+		r.Device = stat.Device
+		r.CollectedTime = stat.When
+		r.Web.CSSID = strings.Replace(cleanName(hostname), ".", "-", -1)
+		r.Web.EnableConfLink = len(stat.Configs) > 0
+		r.Web.EnableTranscriptLink = stat.Transcript && transcriptExists(hostname)
+
+		switch stat.State {
+		case StatePending:
+			r.StatusMessage = "Collecting..."
+			r.Web.Class = "active"
+		case StateTimeout:
+			r.StatusMessage = "Timeout"
+			r.Web.Class = "warning"
+		case StateError:
+			r.StatusMessage = stat.ErrorMessage
+			r.Web.Class = "danger"
+		case StateHostKeyChanged:
+			r.StatusMessage = stat.ErrorMessage
+			r.Web.Class = "danger"
+		case StateSuccess:
+			r.StatusMessage = "OK"
+			r.Web.Class = "success"
+		}
+
+		names := make([]string, 0, len(stat.Diffs))
+		for name := range stat.Diffs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			d := stat.Diffs[name]
+			r.Added += d.Added
+			r.Removed += d.Removed
+			if d.NewFile {
+				r.Diff += fmt.Sprintf("---- %s: new config\n", name)
+			} else {
+				r.Diff += fmt.Sprintf("---- %s:\n%s\n", name, d.Diff)
+			}
+		}
+		if len(names) > 0 {
+			r.Web.EnableDiffLink = true
+			r.Web.Class = "info"
+			r.StatusMessage = "Changed"
+		}
+
+		r.ChangedTime = stat.LastChanged
+		reports = append(reports, r)
+	}
+	return reports
+}
@@ -1,6 +1,9 @@
 package sweet
 
 import (
+	"net"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -68,3 +71,114 @@ func TestWebstatusChangedTimeFormatted(t *testing.T) {
 		}
 	}
 }
+
+func TestWebstatusBuildReports(t *testing.T) {
+	statuses := map[string]DeviceStatus{
+		"sw2.example.com": {
+			Device:  DeviceConfig{Hostname: "sw2.example.com"},
+			State:   StateSuccess,
+			When:    time.Now(),
+			Configs: map[string]string{"config": "hostname sw2"},
+			Diffs: map[string]ConfigDiff{
+				"config":  {Diff: "+hostname sw2", Added: 1, Removed: 2},
+				"version": {NewFile: true},
+			},
+		},
+		"sw1.example.com": {
+			Device:       DeviceConfig{Hostname: "sw1.example.com"},
+			State:        StateError,
+			ErrorMessage: "Missing password prompt",
+		},
+	}
+	reports := buildReports(statuses)
+	if len(reports) != 2 {
+		t.Fatalf("Expected 2 reports but got %d", len(reports))
+	}
+	if reports[0].Device.Hostname != "sw1.example.com" {
+		t.Errorf("Reports not sorted by hostname: %s first", reports[0].Device.Hostname)
+	}
+	if reports[0].Web.Class != "danger" || reports[0].StatusMessage != "Missing password prompt" {
+		t.Errorf("Bad error report: %s %s", reports[0].Web.Class, reports[0].StatusMessage)
+	}
+	if reports[0].Web.EnableConfLink || reports[0].Web.EnableDiffLink {
+		t.Errorf("Error report should not link configs or diffs")
+	}
+	if reports[1].Web.CSSID != "sw2-example-com" {
+		t.Errorf("Bad CSS ID: %s", reports[1].Web.CSSID)
+	}
+	if !reports[1].Web.EnableConfLink || !reports[1].Web.EnableDiffLink {
+		t.Errorf("Changed report should link configs and diffs")
+	}
+	if reports[1].Added != 1 || reports[1].Removed != 2 {
+		t.Errorf("Bad diff stats: +%d -%d", reports[1].Added, reports[1].Removed)
+	}
+	if !strings.Contains(reports[1].Diff, "version: new config") {
+		t.Errorf("Diff missing new file notice: %s", reports[1].Diff)
+	}
+}
+
+func TestWebstatusIndex(t *testing.T) {
+	tmpl, err := loadIndexTemplate()
+	if err != nil {
+		t.Fatalf("Error loading index template: %s", err.Error())
+	}
+	Opts := &SweetOptions{Status: &Status{Status: make(map[string]DeviceStatus)}}
+	Opts.Status.Set(DeviceStatus{Device: DeviceConfig{Hostname: "sw1.example.com"}, State: StateSuccess, When: time.Now(), Configs: map[string]string{"config": "x"}})
+
+	w := httptest.NewRecorder()
+	webIndex(w, httptest.NewRequest("GET", "/", nil), Opts, tmpl)
+	if w.Code != 200 {
+		t.Errorf("Bad index status code: %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "configs/sw1.example.com") {
+		t.Errorf("Index missing config link")
+	}
+
+	w = httptest.NewRecorder()
+	webStatic(w, httptest.NewRequest("GET", "/static/jquery.min.js", nil))
+	if w.Code != 200 || w.Body.Len() < 1 {
+		t.Errorf("Bad static asset response: %d", w.Code)
+	}
+}
+
+func TestWebstatusDuringCollection(t *testing.T) {
+	inTempWorkspace(t)
+	tmpl, err := loadIndexTemplate()
+	if err != nil {
+		t.Fatalf("Error loading index template: %s", err.Error())
+	}
+	shell := &fakeShell{Prompt: "router1#", Responses: map[string]string{"show running-config": "hostname router1\r\n"}}
+	addr := startTestSSHServer(t, passwordServerConfig("sweet", "sweetpw"), shell)
+	host, port, _ := net.SplitHostPort(addr)
+	// no user, so collection fills in the defaults while the pages are read
+	device := DeviceConfig{Hostname: "router1", Method: "cisco", Config: map[string]string{"pass": "sweetpw", "ip": host, "port": port}}
+	Opts := &SweetOptions{Timeout: 5 * time.Second, DefaultUser: "sweet", Insecure: true, DebugTranscript: true, Status: &Status{Status: make(map[string]DeviceStatus)}}
+	Opts.Status.Set(DeviceStatus{Device: device, State: StatePending})
+
+	done := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			webIndex(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), Opts, tmpl)
+			webTranscript(httptest.NewRecorder(), httptest.NewRequest("GET", "/transcripts/router1", nil), Opts)
+		}
+	}()
+	status := collectDevice(device, Opts)
+	close(done)
+	if status.State != StateSuccess {
+		t.Fatalf("Collection failed: %s", status.ErrorMessage)
+	}
+	if _, ok := device.Config["user"]; ok {
+		t.Errorf("Collection should not fill in the shared device settings")
+	}
+	Opts.Status.Set(status)
+	w := httptest.NewRecorder()
+	webTranscript(w, httptest.NewRequest("GET", "/transcripts/router1", nil), Opts)
+	if w.Code != 200 {
+		t.Errorf("Transcript not served after collection: %d", w.Code)
+	}
+}